@@ -0,0 +1,206 @@
+package badge
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"strings"
+	"unicode"
+)
+
+type Style string
+
+const (
+	StyleFlat        Style = "flat"
+	StyleFlatSquare  Style = "flat-square"
+	StyleForTheBadge Style = "for-the-badge"
+)
+
+// ParseStyle returns the badge style, unknown styles default to flat
+func ParseStyle(style string) Style {
+	switch Style(style) {
+	case StyleFlatSquare:
+		return StyleFlatSquare
+	case StyleForTheBadge:
+		return StyleForTheBadge
+	}
+	return StyleFlat
+}
+
+// namedColors maps the shields.io color names to hex colors
+var namedColors = map[string]string{
+	"brightgreen":   "#4c1",
+	"green":         "#97ca00",
+	"yellowgreen":   "#a4a61d",
+	"yellow":        "#dfb317",
+	"orange":        "#fe7d37",
+	"red":           "#e05d44",
+	"blue":          "#007ec6",
+	"grey":          "#555",
+	"gray":          "#555",
+	"lightgrey":     "#9f9f9f",
+	"lightgray":     "#9f9f9f",
+	"crimson":       "#dc143c",
+	"orangered":     "#ff4500",
+	"success":       "#4c1",
+	"important":     "#fe7d37",
+	"critical":      "#e05d44",
+	"informational": "#007ec6",
+	"inactive":      "#9f9f9f",
+}
+
+// RenderSVG renders the badge as svg image, without depending on shields.io
+func RenderSVG(b *Badge, style Style) []byte {
+	label := strings.TrimSpace(b.Label)
+	message := b.Message
+	fontSize := 11.0
+	height := 20.0
+	padding := 6.0
+	if style == StyleForTheBadge {
+		label = strings.ToUpper(label)
+		message = strings.ToUpper(message)
+		fontSize = 10
+		height = 28
+		padding = 12
+	}
+
+	// logo
+	logoWidth := 0.0
+	logo := ""
+	if b.LogoSvg != "" {
+		logoWidth = 14
+		logo = "data:image/svg+xml;base64," + base64.StdEncoding.EncodeToString([]byte(b.LogoSvg))
+	}
+
+	// calculate section widths
+	labelTextWidth := textWidth(label, fontSize, style == StyleForTheBadge)
+	messageTextWidth := textWidth(message, fontSize, style == StyleForTheBadge)
+	labelWidth := labelTextWidth + 2*padding
+	if label == "" {
+		labelWidth = 0
+	}
+	if logoWidth > 0 {
+		if label == "" {
+			labelWidth = logoWidth + 2*padding - 2
+		} else {
+			labelWidth += logoWidth + 3
+		}
+	}
+	messageWidth := messageTextWidth + 2*padding
+	totalWidth := labelWidth + messageWidth
+
+	labelColor := toHexColor(b.LabelColor, "#555")
+	messageColor := toHexColor(b.Color, "#9f9f9f")
+	title := html.EscapeString(strings.TrimSpace(label + ": " + message))
+	if label == "" {
+		title = html.EscapeString(message)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" width="%s" height="%s" role="img" aria-label="%s">`, num(totalWidth), num(height), title)
+	fmt.Fprintf(&buf, `<title>%s</title>`, title)
+
+	// background
+	switch style {
+	case StyleFlat:
+		buf.WriteString(`<linearGradient id="s" x2="0" y2="100%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>`)
+		fmt.Fprintf(&buf, `<clipPath id="r"><rect width="%s" height="%s" rx="3" fill="#fff"/></clipPath>`, num(totalWidth), num(height))
+		buf.WriteString(`<g clip-path="url(#r)">`)
+	default:
+		buf.WriteString(`<g shape-rendering="crispEdges">`)
+	}
+	fmt.Fprintf(&buf, `<rect width="%s" height="%s" fill="%s"/>`, num(labelWidth), num(height), labelColor)
+	fmt.Fprintf(&buf, `<rect x="%s" width="%s" height="%s" fill="%s"/>`, num(labelWidth), num(messageWidth), num(height), messageColor)
+	if style == StyleFlat {
+		fmt.Fprintf(&buf, `<rect width="%s" height="%s" fill="url(#s)"/>`, num(totalWidth), num(height))
+	}
+	buf.WriteString(`</g>`)
+
+	// foreground
+	fontWeight := ""
+	if style == StyleForTheBadge {
+		fontWeight = ` font-weight="bold"`
+	}
+	fmt.Fprintf(&buf, `<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" text-rendering="geometricPrecision" font-size="%s"%s>`, num(fontSize), fontWeight)
+	textOffset := padding
+	if logo != "" {
+		fmt.Fprintf(&buf, `<image x="%s" y="%s" width="%s" height="%s" xlink:href="%s"/>`, num(padding-1), num((height-logoWidth)/2), num(logoWidth), num(logoWidth), logo)
+		textOffset += logoWidth + 3
+	}
+	baseline := height/2 + fontSize/2 - 1
+	if label != "" {
+		writeText(&buf, label, textOffset+labelTextWidth/2, baseline, labelTextWidth, style)
+	}
+	writeText(&buf, message, labelWidth+messageWidth/2, baseline, messageTextWidth, style)
+	buf.WriteString(`</g></svg>`)
+
+	return buf.Bytes()
+}
+
+func writeText(buf *bytes.Buffer, text string, x float64, y float64, width float64, style Style) {
+	escaped := html.EscapeString(text)
+	if style == StyleFlat {
+		// text shadow
+		fmt.Fprintf(buf, `<text aria-hidden="true" x="%s" y="%s" fill="#010101" fill-opacity=".3" textLength="%s">%s</text>`, num(x), num(y+1), num(width), escaped)
+	}
+	fmt.Fprintf(buf, `<text x="%s" y="%s" textLength="%s">%s</text>`, num(x), num(y), num(width), escaped)
+}
+
+// textWidth estimates the rendered width of the text in Verdana
+func textWidth(text string, fontSize float64, bold bool) float64 {
+	width := 0.0
+	for _, r := range text {
+		width += charWidth(r)
+	}
+	width = width * fontSize / 11
+	if bold {
+		// bold text is wider and for-the-badge uses additional letter spacing
+		width = width*1.1 + float64(len([]rune(text)))*1.25
+	}
+	return float64(int(width + 0.5))
+}
+
+// charWidth returns the approximate width of a character in Verdana 11px
+func charWidth(r rune) float64 {
+	switch {
+	case r == ' ':
+		return 3.9
+	case strings.ContainsRune("iIl.,:;|!'", r):
+		return 3.4
+	case strings.ContainsRune("fjrt()[]{}-/\\", r):
+		return 4.6
+	case strings.ContainsRune("mwMW%@", r):
+		return 10.5
+	case unicode.IsDigit(r):
+		return 7
+	case unicode.IsUpper(r):
+		return 7.6
+	case unicode.IsLower(r):
+		return 6.6
+	}
+	return 8.0
+}
+
+// toHexColor converts shields.io color names or hex values without the leading hash to a svg color
+func toHexColor(color string, fallback string) string {
+	if color == "" {
+		return fallback
+	}
+	if hex, ok := namedColors[strings.ToLower(color)]; ok {
+		return hex
+	}
+	if strings.HasPrefix(color, "#") {
+		return color
+	}
+	for _, r := range color {
+		if !unicode.Is(unicode.ASCII_Hex_Digit, r) {
+			return fallback
+		}
+	}
+	return "#" + color
+}
+
+func num(f float64) string {
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.1f", f), "0"), ".")
+}
@@ -0,0 +1,59 @@
+package badge
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestRenderSVG(t *testing.T) {
+	for _, theme := range []string{"default", "renovate"} {
+		for _, style := range []Style{StyleFlat, StyleFlatSquare, StyleForTheBadge} {
+			t.Run(theme+"/"+string(style), func(t *testing.T) {
+				svg := RenderSVG(NewDependencyBadge("0.6.2 - 3/3 ok", Success, theme), style)
+
+				// must be well-formed xml
+				decoder := xml.NewDecoder(strings.NewReader(string(svg)))
+				for {
+					_, err := decoder.Token()
+					if err != nil {
+						if err.Error() == "EOF" {
+							break
+						}
+						t.Fatalf("RenderSVG() returned invalid xml: %v", err)
+					}
+				}
+
+				message := "0.6.2 - 3/3 ok"
+				if style == StyleForTheBadge {
+					message = strings.ToUpper(message)
+				}
+				if !strings.Contains(string(svg), ">"+message+"</text>") {
+					t.Errorf("RenderSVG() does not contain message %q", message)
+				}
+				if !strings.Contains(string(svg), `fill="#4c1"`) {
+					t.Errorf("RenderSVG() does not contain the success color")
+				}
+				if !strings.Contains(string(svg), "data:image/svg+xml;base64,") {
+					t.Errorf("RenderSVG() does not contain the logo")
+				}
+			})
+		}
+	}
+}
+
+func TestToHexColor(t *testing.T) {
+	tests := map[string]string{
+		"brightgreen": "#4c1",
+		"2a2f64":      "#2a2f64",
+		"#2a2f64":     "#2a2f64",
+		"invalid":     "#555",
+		"":            "#555",
+	}
+
+	for input, want := range tests {
+		if got := toHexColor(input, "#555"); got != want {
+			t.Errorf("toHexColor(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package check
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	// githubDataEscaper escapes the message of a workflow command
+	githubDataEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+	// githubPropertyEscaper escapes the properties of a workflow command, e.g. file or title
+	githubPropertyEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+)
+
+// WriteGitHubAnnotations writes one GitHub Actions workflow command per dependency, see https://docs.github.com/en/actions/reference/workflow-commands-for-github-actions
+//
+// Failures are reported as error, warnings as warning and passed dependencies as notice annotations.
+func WriteGitHubAnnotations(w io.Writer, report Report, options ReportOptions) error {
+	for _, result := range report.Results {
+		command := "notice"
+		switch result.Outcome {
+		case OutcomeFail:
+			command = "error"
+		case OutcomeWarn:
+			command = "warning"
+		}
+
+		properties := []string{"title=" + githubPropertyEscaper.Replace(fmt.Sprintf("%s: %s", result.Coordinate, result.Status))}
+		if options.File != "" {
+			properties = append([]string{"file=" + githubPropertyEscaper.Replace(filepath.ToSlash(options.File))}, properties...)
+		}
+
+		if _, err := fmt.Fprintf(w, "::%s %s::%s\n", command, strings.Join(properties, ","), githubDataEscaper.Replace(result.message())); err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "::notice title=%s::%d passed, %d warnings, %d failures\n", ToolName, report.Passed, report.Warnings, report.Failures)
+	return err
+}
@@ -0,0 +1,143 @@
+package check
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/model"
+)
+
+// InputType is the format of a dependency input file
+type InputType string
+
+const (
+	InputCoordinates    InputType = "coordinates"     // one maven coordinate or purl per line
+	InputPom            InputType = "pom"             // pom.xml, the transitive dependencies are resolved
+	InputGradleLockfile InputType = "gradle-lockfile" // gradle.lockfile generated by gradle dependency locking
+	InputDependencyList InputType = "dependency-list" // output of mvn dependency:list
+)
+
+// dependencyListMarker is part of the mvn dependency:list output
+const dependencyListMarker = "The following files have been resolved:"
+
+var ErrUnsupportedInput = errors.New("unsupported input type")
+
+// DetectInputType returns the input type based on the file name and content
+func DetectInputType(file string, content []byte) InputType {
+	name := filepath.Base(file)
+	switch {
+	case name == "pom.xml" || strings.HasSuffix(name, ".pom"):
+		return InputPom
+	case strings.HasSuffix(name, ".lockfile"):
+		return InputGradleLockfile
+	case bytes.Contains(content, []byte(dependencyListMarker)):
+		return InputDependencyList
+	}
+	return InputCoordinates
+}
+
+// ParseInput parses a coordinates list, gradle lockfile or maven dependency list, poms need to be resolved using a DependencyResolver
+func ParseInput(inputType InputType, content []byte) ([]string, error) {
+	switch inputType {
+	case InputCoordinates:
+		return ParseCoordinates(content), nil
+	case InputGradleLockfile:
+		return ParseGradleLockfile(content), nil
+	case InputDependencyList:
+		return ParseDependencyList(content), nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedInput, inputType)
+}
+
+// ParseCoordinates parses a list of coordinates (maven coordinates or purls), one per line, empty lines and lines starting with # are ignored
+func ParseCoordinates(content []byte) []string {
+	var coordinates []string
+	for _, line := range lines(content) {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		coordinates = appendUnique(coordinates, line)
+	}
+	return coordinates
+}
+
+// ParseGradleLockfile returns the coordinates of a gradle.lockfile, e.g. com.google.guava:guava:31.1-jre=compileClasspath,runtimeClasspath
+//
+// Dependencies that are only part of test configurations are ignored.
+func ParseGradleLockfile(content []byte) []string {
+	var coordinates []string
+	for _, line := range lines(content) {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		coordinate, configurations, _ := strings.Cut(line, "=")
+		if strings.Count(coordinate, ":") != 2 {
+			continue // e.g. empty=annotationProcessor
+		}
+		if isTestOnly(strings.Split(configurations, ",")) {
+			continue
+		}
+		coordinates = appendUnique(coordinates, coordinate)
+	}
+	return coordinates
+}
+
+// ParseDependencyList returns the coordinates of the mvn dependency:list output, e.g. [INFO]    org.slf4j:slf4j-api:jar:1.7.36:compile
+//
+// Dependencies with the scopes test, provided and system are ignored, as they are not part of the released artifact.
+func ParseDependencyList(content []byte) []string {
+	var coordinates []string
+	for _, line := range lines(content) {
+		line = strings.TrimSpace(strings.TrimPrefix(line, "[INFO]"))
+		line, _, _ = strings.Cut(line, " ") // e.g. " -- module org.slf4j" suffix
+
+		var groupId, artifactId, version, scope string
+		parts := strings.Split(line, ":")
+		switch len(parts) {
+		case 5: // groupId:artifactId:type:version:scope
+			groupId, artifactId, version, scope = parts[0], parts[1], parts[3], parts[4]
+		case 6: // groupId:artifactId:type:classifier:version:scope
+			groupId, artifactId, version, scope = parts[0], parts[1], parts[4], parts[5]
+		default:
+			continue
+		}
+		if scope != "compile" && scope != "runtime" {
+			continue
+		}
+
+		gav := model.GAV{GroupId: groupId, ArtifactId: artifactId, Version: version}
+		coordinates = appendUnique(coordinates, gav.Coordinate())
+	}
+	return coordinates
+}
+
+func isTestOnly(configurations []string) bool {
+	for _, configuration := range configurations {
+		if !strings.HasPrefix(strings.ToLower(strings.TrimSpace(configuration)), "test") {
+			return false
+		}
+	}
+	return true
+}
+
+func lines(content []byte) []string {
+	var result []string
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		result = append(result, strings.TrimSpace(scanner.Text()))
+	}
+	return result
+}
+
+func appendUnique(values []string, value string) []string {
+	if slices.Contains(values, value) {
+		return values
+	}
+	return append(values, value)
+}
@@ -0,0 +1,39 @@
+package check
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseGradleLockfile(t *testing.T) {
+	content := []byte(`# This is a Gradle generated file for dependency locking.
+# Manual edits can break the build and are not advised.
+# This file is expected to be part of source control.
+com.google.guava:guava:31.1-jre=compileClasspath,runtimeClasspath
+junit:junit:4.13.2=testCompileClasspath,testRuntimeClasspath
+empty=annotationProcessor
+`)
+
+	if diff := cmp.Diff([]string{"com.google.guava:guava:31.1-jre"}, ParseGradleLockfile(content)); diff != "" {
+		t.Errorf("ParseGradleLockfile() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseDependencyList(t *testing.T) {
+	content := []byte(`[INFO] --- dependency:3.6.1:list (default-cli) @ app ---
+[INFO] 
+[INFO] The following files have been resolved:
+[INFO]    org.slf4j:slf4j-api:jar:2.0.9:compile -- module org.slf4j
+[INFO]    com.example:foo:jar:linux-x86_64:1.0:runtime
+[INFO]    org.junit.jupiter:junit-jupiter-api:jar:5.10.0:test -- module org.junit.jupiter.api
+[INFO] 
+`)
+
+	if DetectInputType("deps.txt", content) != InputDependencyList {
+		t.Errorf("expected dependency list to be detected")
+	}
+	if diff := cmp.Diff([]string{"org.slf4j:slf4j-api:2.0.9", "com.example:foo:1.0"}, ParseDependencyList(content)); diff != "" {
+		t.Errorf("ParseDependencyList() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,104 @@
+package check
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name       string           `xml:"name,attr"`
+	ClassName  string           `xml:"classname,attr"`
+	Properties *junitProperties `xml:"properties,omitempty"`
+	Failure    *junitResult     `xml:"failure,omitempty"`
+	Skipped    *junitResult     `xml:"skipped,omitempty"`
+	SystemOut  string           `xml:"system-out,omitempty"`
+}
+
+type junitProperties struct {
+	Properties []junitProperty `xml:"property"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitResult struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes the report as JUnit XML, each dependency is a test case
+//
+// Failures are reported as failed test cases, JUnit has no concept of warnings, so they are reported as skipped test cases.
+func WriteJUnit(w io.Writer, report Report) error {
+	suite := junitTestSuite{
+		Name:      "reproducibility",
+		Tests:     len(report.Results),
+		Failures:  report.Failures,
+		Skipped:   report.Warnings,
+		TestCases: make([]junitTestCase, 0, len(report.Results)),
+	}
+	for _, result := range report.Results {
+		testCase := junitTestCase{
+			Name:      result.Coordinate,
+			ClassName: result.Coordinate, // unparsable coordinates
+			SystemOut: result.message(),
+		}
+		if result.GroupID != "" {
+			testCase.ClassName = result.GroupID + "." + result.ArtifactID
+		}
+
+		properties := []junitProperty{{Name: "status", Value: string(result.Status)}}
+		if result.RebuildProjectUrl != "" {
+			properties = append(properties, junitProperty{Name: "rebuild_project_url", Value: result.RebuildProjectUrl})
+		}
+		for _, file := range result.NonReproducibleFiles {
+			properties = append(properties, junitProperty{Name: "non_reproducible_file", Value: file})
+		}
+		testCase.Properties = &junitProperties{Properties: properties}
+
+		switch result.Outcome {
+		case OutcomeFail:
+			testCase.Failure = &junitResult{Message: result.Coordinate + " is " + string(result.Status), Type: string(result.Status), Text: result.message()}
+		case OutcomeWarn:
+			testCase.Skipped = &junitResult{Message: result.Coordinate + " is " + string(result.Status)}
+		}
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(junitTestSuites{
+		Name:     ToolName,
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Skipped:  suite.Skipped,
+		Suites:   []junitTestSuite{suite},
+	}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,102 @@
+package check
+
+import (
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/model"
+)
+
+// Outcome is the result of the policy evaluation for a single dependency
+type Outcome string
+
+const (
+	OutcomePass Outcome = "pass"
+	OutcomeWarn Outcome = "warn"
+	OutcomeFail Outcome = "fail"
+)
+
+// Policy decides which reproducibility status fails or warns, allowed dependencies always pass
+type Policy struct {
+	Fail []model.ReproducibilityStatus
+	Warn []model.ReproducibilityStatus
+	// Allow contains patterns (groupId:artifactId or groupId:artifactId:version) of dependencies that are accepted regardless of their status, * can be used as wildcard
+	Allow []string
+}
+
+// DefaultPolicy fails on non-reproducible dependencies and warns on dependencies that are not part of the index or not rebuilt yet
+var DefaultPolicy = Policy{
+	Fail: []model.ReproducibilityStatus{model.StatusNotReproducible, model.StatusPartiallyReproducible},
+	Warn: []model.ReproducibilityStatus{model.StatusUnknown, model.StatusPending},
+}
+
+// Result is the policy outcome of a single dependency
+type Result struct {
+	model.LookupResult
+	Outcome Outcome `json:"outcome"`
+	Allowed bool    `json:"allowed,omitempty"` // the dependency matches an allowlist pattern
+}
+
+// Report contains the outcome of all dependencies, in the order of the input
+type Report struct {
+	Results  []Result `json:"results"`
+	Passed   int      `json:"passed"`
+	Warnings int      `json:"warnings"`
+	Failures int      `json:"failures"`
+}
+
+// Failed returns true if at least one dependency failed the policy
+func (r Report) Failed() bool {
+	return r.Failures > 0
+}
+
+// Evaluate applies the policy to the lookup results
+func (p Policy) Evaluate(results []model.LookupResult) Report {
+	report := Report{Results: make([]Result, 0, len(results))}
+	for _, lookup := range results {
+		result := Result{LookupResult: lookup, Outcome: OutcomePass}
+		switch {
+		case p.Allowed(lookup):
+			result.Allowed = true
+		case slices.Contains(p.Fail, lookup.Status):
+			result.Outcome = OutcomeFail
+		case slices.Contains(p.Warn, lookup.Status) || lookup.Error != "":
+			result.Outcome = OutcomeWarn
+		}
+
+		switch result.Outcome {
+		case OutcomePass:
+			report.Passed++
+		case OutcomeWarn:
+			report.Warnings++
+		case OutcomeFail:
+			report.Failures++
+		}
+		report.Results = append(report.Results, result)
+	}
+	return report
+}
+
+// Allowed returns true if the dependency matches one of the allowlist patterns
+func (p Policy) Allowed(result model.LookupResult) bool {
+	values := []string{result.GroupID, result.ArtifactID, result.Version}
+	for _, pattern := range p.Allow {
+		parts := strings.Split(pattern, ":")
+		if len(parts) < 2 || len(parts) > 3 {
+			continue
+		}
+
+		matches := true
+		for i, part := range parts {
+			if ok, _ := path.Match(part, values[i]); !ok {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,34 @@
+package check
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/model"
+)
+
+func TestPolicyEvaluate(t *testing.T) {
+	policy := DefaultPolicy
+	policy.Allow = []string{"com.example.legacy:*"}
+
+	report := policy.Evaluate([]model.LookupResult{
+		{Coordinate: "com.example:ok:1.0", GroupID: "com.example", ArtifactID: "ok", Version: "1.0", Status: model.StatusReproducible},
+		{Coordinate: "com.example:ko:1.0", GroupID: "com.example", ArtifactID: "ko", Version: "1.0", Status: model.StatusNotReproducible},
+		{Coordinate: "com.example:new:1.0", GroupID: "com.example", ArtifactID: "new", Version: "1.0", Status: model.StatusUnknown},
+		{Coordinate: "com.example.legacy:old:1.0", GroupID: "com.example.legacy", ArtifactID: "old", Version: "1.0", Status: model.StatusNotReproducible},
+	})
+
+	var outcomes []Outcome
+	for _, result := range report.Results {
+		outcomes = append(outcomes, result.Outcome)
+	}
+	if diff := cmp.Diff([]Outcome{OutcomePass, OutcomeFail, OutcomeWarn, OutcomePass}, outcomes); diff != "" {
+		t.Errorf("Evaluate() mismatch (-want +got):\n%s", diff)
+	}
+	if !report.Failed() || report.Passed != 2 || report.Warnings != 1 || report.Failures != 1 {
+		t.Errorf("unexpected report counts %+v", report)
+	}
+	if !report.Results[3].Allowed {
+		t.Errorf("expected allowlisted dependency to be marked as allowed")
+	}
+}
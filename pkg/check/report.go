@@ -0,0 +1,87 @@
+package check
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Format is the output format of a check report
+type Format string
+
+const (
+	FormatText   Format = "text"   // one line per dependency and a summary
+	FormatJSON   Format = "json"   // the report as json
+	FormatSARIF  Format = "sarif"  // SARIF 2.1.0, e.g. for GitHub code scanning
+	FormatJUnit  Format = "junit"  // JUnit XML, one test case per dependency
+	FormatGitHub Format = "github" // GitHub Actions workflow commands, shown as annotations
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported report format")
+
+// ReportOptions contains additional information for the report formats that reference the checked file or the tool
+type ReportOptions struct {
+	File        string // input file, used as location of the results if set
+	ToolVersion string // version of the tool that created the report
+}
+
+// WriteReport writes the report in the requested format
+func WriteReport(w io.Writer, format Format, report Report, options ReportOptions) error {
+	switch format {
+	case FormatText:
+		return WriteText(w, report)
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	case FormatSARIF:
+		return WriteSARIF(w, report, options)
+	case FormatJUnit:
+		return WriteJUnit(w, report)
+	case FormatGitHub:
+		return WriteGitHubAnnotations(w, report, options)
+	}
+	return fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+}
+
+// WriteText writes one line per dependency and a summary
+func WriteText(w io.Writer, report Report) error {
+	for _, result := range report.Results {
+		details := string(result.Status)
+		if result.Allowed {
+			details += ", allowed"
+		}
+		if result.Error != "" {
+			details += ", " + result.Error
+		}
+		if len(result.NonReproducibleFiles) > 0 {
+			details += ", non-reproducible: " + strings.Join(result.NonReproducibleFiles, " ")
+		}
+		if _, err := fmt.Fprintf(w, "%-4s  %s (%s)\n", strings.ToUpper(string(result.Outcome)), result.Coordinate, details); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "\n%d passed, %d warnings, %d failures\n", report.Passed, report.Warnings, report.Failures)
+	return err
+}
+
+// message returns a human-readable description of the result, used by all formats that show a single message per dependency
+func (r Result) message() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s is %s", r.Coordinate, r.Status)
+	if r.Allowed {
+		sb.WriteString(" (allowed)")
+	}
+	if r.Error != "" {
+		fmt.Fprintf(&sb, ": %s", r.Error)
+	}
+	if len(r.NonReproducibleFiles) > 0 {
+		fmt.Fprintf(&sb, "\nNon-reproducible files: %s", strings.Join(r.NonReproducibleFiles, ", "))
+	}
+	if r.RebuildProjectUrl != "" {
+		fmt.Fprintf(&sb, "\nRebuild project: %s", r.RebuildProjectUrl)
+	}
+	return sb.String()
+}
@@ -0,0 +1,109 @@
+package check
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/model"
+)
+
+var testReport = DefaultPolicy.Evaluate([]model.LookupResult{
+	{Coordinate: "com.example:ok:1.0", GroupID: "com.example", ArtifactID: "ok", Version: "1.0", Status: model.StatusReproducible, RebuildProjectUrl: "https://example.com/ok"},
+	{Coordinate: "com.example:ko:1.0", GroupID: "com.example", ArtifactID: "ko", Version: "1.0", Status: model.StatusNotReproducible, RebuildProjectUrl: "https://example.com/ko", NonReproducibleFiles: []string{"ko-1.0.jar", "ko-1.0-sources.jar"}},
+	{Coordinate: "com.example:new:1.0", GroupID: "com.example", ArtifactID: "new", Version: "1.0", Status: model.StatusUnknown},
+})
+
+func TestWriteSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteSARIF(&buf, testReport, ReportOptions{File: "pom.xml", ToolVersion: "1.0.0"}); err != nil {
+		t.Fatalf("WriteSARIF() error = %v", err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("unexpected sarif log %+v", log)
+	}
+
+	var rules []string
+	for _, rule := range log.Runs[0].Tool.Driver.Rules {
+		rules = append(rules, rule.ID)
+	}
+	if diff := cmp.Diff([]string{"reproducible", "not-reproducible", "unknown"}, rules); diff != "" {
+		t.Errorf("rules mismatch (-want +got):\n%s", diff)
+	}
+
+	var levels []string
+	for _, result := range log.Runs[0].Results {
+		levels = append(levels, result.Kind+"/"+result.Level)
+	}
+	if diff := cmp.Diff([]string{"pass/none", "fail/error", "fail/warning"}, levels); diff != "" {
+		t.Errorf("levels mismatch (-want +got):\n%s", diff)
+	}
+
+	failed := log.Runs[0].Results[1]
+	want := sarifProperties{Status: model.StatusNotReproducible, RebuildProjectUrl: "https://example.com/ko", NonReproducibleFiles: []string{"ko-1.0.jar", "ko-1.0-sources.jar"}}
+	if diff := cmp.Diff(want, failed.Properties); diff != "" {
+		t.Errorf("properties mismatch (-want +got):\n%s", diff)
+	}
+	if failed.RuleIndex != 1 || failed.Locations[0].PhysicalLocation.ArtifactLocation.URI != "pom.xml" {
+		t.Errorf("unexpected result %+v", failed)
+	}
+}
+
+func TestWriteJUnit(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJUnit(&buf, testReport); err != nil {
+		t.Fatalf("WriteJUnit() error = %v", err)
+	}
+
+	var suites junitTestSuites
+	if err := xml.Unmarshal(buf.Bytes(), &suites); err != nil {
+		t.Fatalf("invalid xml: %v", err)
+	}
+	if suites.Tests != 3 || suites.Failures != 1 || suites.Skipped != 1 {
+		t.Errorf("unexpected counts %+v", suites)
+	}
+
+	cases := suites.Suites[0].TestCases
+	if cases[0].Failure != nil || cases[0].Skipped != nil {
+		t.Errorf("expected passed test case, got %+v", cases[0])
+	}
+	if cases[1].Failure == nil || !strings.Contains(cases[1].Failure.Text, "ko-1.0-sources.jar") || !strings.Contains(cases[1].Failure.Text, "https://example.com/ko") {
+		t.Errorf("expected failure with files and rebuild project, got %+v", cases[1].Failure)
+	}
+	if cases[2].Skipped == nil {
+		t.Errorf("expected skipped test case, got %+v", cases[2])
+	}
+}
+
+func TestWriteGitHubAnnotations(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteGitHubAnnotations(&buf, testReport, ReportOptions{File: "pom.xml"}); err != nil {
+		t.Fatalf("WriteGitHubAnnotations() error = %v", err)
+	}
+
+	want := []string{
+		"::notice file=pom.xml,title=com.example%3Aok%3A1.0%3A reproducible::com.example:ok:1.0 is reproducible%0ARebuild project: https://example.com/ok",
+		"::error file=pom.xml,title=com.example%3Ako%3A1.0%3A not-reproducible::com.example:ko:1.0 is not-reproducible%0ANon-reproducible files: ko-1.0.jar, ko-1.0-sources.jar%0ARebuild project: https://example.com/ko",
+		"::warning file=pom.xml,title=com.example%3Anew%3A1.0%3A unknown::com.example:new:1.0 is unknown",
+		"::notice title=jvm-repo-rebuild-index::1 passed, 1 warnings, 1 failures",
+	}
+	if diff := cmp.Diff(want, strings.Split(strings.TrimSpace(buf.String()), "\n")); diff != "" {
+		t.Errorf("WriteGitHubAnnotations() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestWriteReportUnsupportedFormat(t *testing.T) {
+	err := WriteReport(&bytes.Buffer{}, "csv", testReport, ReportOptions{})
+	if !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("WriteReport() error = %v, want %v", err, ErrUnsupportedFormat)
+	}
+}
@@ -0,0 +1,197 @@
+package check
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/model"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+	// ToolName is the name of the tool in the SARIF and annotation reports
+	ToolName           = "jvm-repo-rebuild-index"
+	toolInformationUri = "https://github.com/PhilippHeuer/jvm-repo-rebuild-index"
+	statusHelpUri      = "https://reproducible-builds.org/docs/jvm/"
+)
+
+// statusDescriptions are used as rule descriptions, each reproducibility status is a separate rule
+var statusDescriptions = map[model.ReproducibilityStatus]string{
+	model.StatusReproducible:          "The dependency has been rebuilt and all files are reproducible",
+	model.StatusPartiallyReproducible: "The dependency has been rebuilt, but some files are not reproducible",
+	model.StatusNotReproducible:       "The dependency has been rebuilt, but the files are not reproducible",
+	model.StatusPending:               "The project of the dependency is indexed, but the version has not been rebuilt yet",
+	model.StatusUnknown:               "The dependency is not part of the index",
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationUri string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	Name             string       `json:"name"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+	HelpUri          string       `json:"helpUri"`
+}
+
+type sarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	RuleIndex           int               `json:"ruleIndex"`
+	Kind                string            `json:"kind"`
+	Level               string            `json:"level"`
+	Message             sarifMessage      `json:"message"`
+	Locations           []sarifLocation   `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+	Properties          sarifProperties   `json:"properties"`
+}
+
+type sarifMessage struct {
+	Text     string `json:"text"`
+	Markdown string `json:"markdown,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+type sarifProperties struct {
+	Status               model.ReproducibilityStatus `json:"status"`
+	Allowed              bool                        `json:"allowed,omitempty"`
+	RebuildProjectUrl    string                      `json:"rebuildProjectUrl,omitempty"`
+	NonReproducibleFiles []string                    `json:"nonReproducibleFiles,omitempty"`
+}
+
+// WriteSARIF writes the report as SARIF 2.1.0 log, each reproducibility status is a rule and each dependency a result
+//
+// Passed dependencies are included with kind pass, failures are reported with level error and warnings with level warning.
+func WriteSARIF(w io.Writer, report Report, options ReportOptions) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           ToolName,
+			Version:        options.ToolVersion,
+			InformationUri: toolInformationUri,
+			Rules:          make([]sarifRule, 0),
+		}},
+		Results: make([]sarifResult, 0, len(report.Results)),
+	}
+
+	ruleIndex := make(map[model.ReproducibilityStatus]int)
+	for _, result := range report.Results {
+		index, ok := ruleIndex[result.Status]
+		if !ok {
+			index = len(run.Tool.Driver.Rules)
+			ruleIndex[result.Status] = index
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+				ID:               string(result.Status),
+				Name:             sarifRuleName(result.Status),
+				ShortDescription: sarifMessage{Text: statusDescriptions[result.Status]},
+				HelpUri:          statusHelpUri,
+			})
+		}
+
+		location := sarifLocation{
+			LogicalLocations: []sarifLogicalLocation{{FullyQualifiedName: result.Coordinate, Kind: "package"}},
+		}
+		if options.File != "" {
+			location.PhysicalLocation = &sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(options.File)}}
+		}
+
+		kind, level := "fail", "error"
+		switch result.Outcome {
+		case OutcomePass:
+			kind, level = "pass", "none"
+		case OutcomeWarn:
+			level = "warning"
+		}
+
+		run.Results = append(run.Results, sarifResult{
+			RuleID:              string(result.Status),
+			RuleIndex:           index,
+			Kind:                kind,
+			Level:               level,
+			Message:             sarifMessage{Text: result.message(), Markdown: result.markdown()},
+			Locations:           []sarifLocation{location},
+			PartialFingerprints: map[string]string{"coordinate/v1": result.Coordinate},
+			Properties: sarifProperties{
+				Status:               result.Status,
+				Allowed:              result.Allowed,
+				RebuildProjectUrl:    result.RebuildProjectUrl,
+				NonReproducibleFiles: result.NonReproducibleFiles,
+			},
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sarifLog{Schema: sarifSchema, Version: sarifVersion, Runs: []sarifRun{run}})
+}
+
+// sarifRuleName returns the status in PascalCase, e.g. PartiallyReproducible
+func sarifRuleName(status model.ReproducibilityStatus) string {
+	var sb strings.Builder
+	for _, part := range strings.Split(string(status), "-") {
+		if part != "" {
+			sb.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+	}
+	return sb.String()
+}
+
+// markdown returns the message of the result with links to the rebuild project
+func (r Result) markdown() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "`%s` is **%s**", r.Coordinate, r.Status)
+	if r.Allowed {
+		sb.WriteString(" (allowed)")
+	}
+	if r.Error != "" {
+		fmt.Fprintf(&sb, ": %s", r.Error)
+	}
+	if len(r.NonReproducibleFiles) > 0 {
+		sb.WriteString("\n\nNon-reproducible files:\n")
+		for _, file := range r.NonReproducibleFiles {
+			fmt.Fprintf(&sb, "\n- `%s`", file)
+		}
+	}
+	if r.RebuildProjectUrl != "" {
+		fmt.Fprintf(&sb, "\n\n[Rebuild project](%s)", r.RebuildProjectUrl)
+	}
+	return sb.String()
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"log/slog"
+	"os"
+	"slices"
+
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/check"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/model"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/service"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+func checkCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "check [coordinate...]",
+		Short: "check the reproducibility of dependencies, exits with code 1 if a dependency fails the policy",
+		Run: func(cmd *cobra.Command, args []string) {
+			file, _ := cmd.Flags().GetString("file")
+			inputType, _ := cmd.Flags().GetString("type")
+			failOn, _ := cmd.Flags().GetStringSlice("fail-on")
+			warnOn, _ := cmd.Flags().GetStringSlice("warn-on")
+			allow, _ := cmd.Flags().GetStringSlice("allow")
+			allowlistFile, _ := cmd.Flags().GetString("allowlist")
+			format, _ := cmd.Flags().GetString("format")
+			output, _ := cmd.Flags().GetString("output")
+			if file == "" && len(args) == 0 {
+				slog.Error("coordinates or an input file are required")
+				os.Exit(1)
+			}
+
+			// policy
+			policy := check.Policy{Allow: allow}
+			for _, status := range failOn {
+				policy.Fail = append(policy.Fail, model.ReproducibilityStatus(status))
+			}
+			for _, status := range warnOn {
+				policy.Warn = append(policy.Warn, model.ReproducibilityStatus(status))
+			}
+			if allowlistFile != "" {
+				content, err := os.ReadFile(allowlistFile)
+				if err != nil {
+					slog.Error("failed to read allowlist", "file", allowlistFile, "error", err)
+					os.Exit(1)
+				}
+				policy.Allow = append(policy.Allow, check.ParseCoordinates(content)...)
+			}
+
+			// collect coordinates
+			coordinates := slices.Clone(args)
+			if file != "" {
+				fileCoordinates, err := readCheckInput(cmd, file, check.InputType(inputType))
+				if err != nil {
+					slog.Error("failed to read dependencies", "file", file, "error", err)
+					os.Exit(1)
+				}
+				coordinates = append(coordinates, fileCoordinates...)
+			}
+			slog.Info("checking dependencies", "count", len(coordinates))
+
+			// lookup and evaluate
+			lookupService, registry, concurrency := lookupConfig(cmd)
+			report := policy.Evaluate(service.LookupStatuses(lookupService, registry, coordinates, concurrency))
+			if err := writeCheckReport(report, check.Format(format), output, file); err != nil {
+				slog.Error("failed to write report", "format", format, "output", output, "error", err)
+				os.Exit(1)
+			}
+			slog.Info("checked dependencies", "passed", report.Passed, "warnings", report.Warnings, "failures", report.Failures)
+
+			if report.Failed() {
+				os.Exit(1)
+			}
+		},
+	}
+
+	cmd.Flags().StringP("file", "f", "", "Input file: coordinates list, pom.xml, gradle.lockfile or mvn dependency:list output")
+	cmd.Flags().String("type", "auto", "Type of the input file (auto, coordinates, pom, gradle-lockfile, dependency-list)")
+	cmd.Flags().StringSlice("fail-on", statusNames(check.DefaultPolicy.Fail), "Status that fail the check")
+	cmd.Flags().StringSlice("warn-on", statusNames(check.DefaultPolicy.Warn), "Status that are reported as warning")
+	cmd.Flags().StringSlice("allow", nil, "Allowed dependencies (groupId:artifactId[:version], supports * as wildcard)")
+	cmd.Flags().String("allowlist", "", "File with allowed dependencies, one pattern per line")
+	cmd.Flags().String("format", string(check.FormatText), "Report format (text, json, sarif, junit, github)")
+	cmd.Flags().StringP("output", "o", "", "Report file (defaults to stdout)")
+	cmd.Flags().String("pom-registry", "repo.maven.apache.org/maven2", "Maven repository used to resolve the dependencies of a pom.xml (supports file:// for local repositories)")
+	addLookupFlags(cmd)
+
+	return cmd
+}
+
+// readCheckInput returns the coordinates of the input file, the transitive dependencies of poms are resolved using the pom registry
+func readCheckInput(cmd *cobra.Command, file string, inputType check.InputType) ([]string, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	if inputType == "auto" {
+		inputType = check.DetectInputType(file, content)
+	}
+	if inputType != check.InputPom {
+		return check.ParseInput(inputType, content)
+	}
+
+	pom, err := util.ParseXML[model.PomProject](content)
+	if err != nil {
+		return nil, err
+	}
+	lookupService, _, _ := lookupConfig(cmd)
+	pomRegistry, _ := cmd.Flags().GetString("pom-registry")
+	dependencies, err := service.NewDependencyResolver(lookupService, pomRegistry).ResolvePom(&pom)
+	if err != nil {
+		return nil, err
+	}
+
+	var coordinates []string
+	for _, dep := range dependencies {
+		coordinates = append(coordinates, dep.Coordinate())
+	}
+	return coordinates, nil
+}
+
+// writeCheckReport writes the report to the output file or stdout, the input file is referenced as location of the results
+func writeCheckReport(report check.Report, format check.Format, output string, file string) error {
+	w := os.Stdout
+	if output != "" {
+		f, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	return check.WriteReport(w, format, report, check.ReportOptions{File: file, ToolVersion: Version})
+}
+
+func statusNames(statuses []model.ReproducibilityStatus) []string {
+	var names []string
+	for _, status := range statuses {
+		names = append(names, string(status))
+	}
+	return names
+}
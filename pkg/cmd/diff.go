@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/diff"
+	"github.com/spf13/cobra"
+)
+
+func diffCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff <old> <new>",
+		Short: "compare two index output directories or catalogs and report added, removed and changed projects and versions",
+		Args:  cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			format, _ := cmd.Flags().GetString("format")
+			output, _ := cmd.Flags().GetString("output")
+
+			previous, err := diff.LoadSnapshot(args[0])
+			if err != nil {
+				slog.Error("failed to load snapshot", "path", args[0], "error", err)
+				os.Exit(1)
+			}
+			current, err := diff.LoadSnapshot(args[1])
+			if err != nil {
+				slog.Error("failed to load snapshot", "path", args[1], "error", err)
+				os.Exit(1)
+			}
+
+			report, err := diff.Compare(previous, current)
+			if err != nil {
+				slog.Error("failed to compare snapshots", "old", args[0], "new", args[1], "error", err)
+				os.Exit(1)
+			}
+			report.Old, report.New = args[0], args[1]
+
+			w := os.Stdout
+			if output != "" {
+				f, createErr := os.Create(output)
+				if createErr != nil {
+					slog.Error("failed to create output file", "file", output, "error", createErr)
+					os.Exit(1)
+				}
+				defer f.Close()
+				w = f
+			}
+			if err = diff.WriteReport(w, diff.Format(format), report); err != nil {
+				slog.Error("failed to write report", "format", format, "error", err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	cmd.Flags().String("format", string(diff.FormatText), "Report format (text, json, markdown)")
+	cmd.Flags().StringP("output", "o", "", "Report file (defaults to stdout)")
+
+	return cmd
+}
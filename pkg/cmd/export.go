@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/export"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+func exportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "export the index into other formats",
+		Run: func(cmd *cobra.Command, args []string) {
+			_ = cmd.Help()
+			os.Exit(0)
+		},
+	}
+
+	cmd.AddCommand(exportSQLiteCmd())
+
+	return cmd
+}
+
+func exportSQLiteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sqlite",
+		Short: "export the index as sqlite database",
+		Run: func(cmd *cobra.Command, args []string) {
+			inputDir, _ := cmd.Flags().GetString("input")
+			outputFile, _ := cmd.Flags().GetString("output")
+			if inputDir == "" || outputFile == "" {
+				slog.Error("input directory and output file are required")
+				os.Exit(1)
+			}
+			slog.Info("exporting sqlite database", "inputDir", inputDir, "outputFile", outputFile)
+
+			files, filesErr := util.FindFiles(inputDir, "maven-metadata.xml")
+			if filesErr != nil {
+				slog.Error("failed to find maven-metadata.xml files", "error", filesErr)
+				os.Exit(1)
+			}
+
+			results := processFiles(collectBuildSources(inputDir, files))
+			depMetadata, projectMetadata := mergeBuildResults(results)
+
+			if err := export.WriteSQLite(outputFile, projectMetadata, depMetadata); err != nil {
+				slog.Error("failed to write sqlite database", "error", err)
+				os.Exit(1)
+			}
+			slog.Info("exported sqlite database", "projects", len(projectMetadata), "artifacts", len(depMetadata))
+		},
+	}
+
+	cmd.Flags().StringP("input", "i", "", "Input Directory")
+	cmd.Flags().StringP("output", "o", "", "Output File")
+
+	return cmd
+}
@@ -2,15 +2,21 @@ package cmd
 
 import (
 	"errors"
+	"fmt"
 	"log/slog"
+	"maps"
 	"os"
 	"path/filepath"
 	"slices"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/feed"
 	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/jvmrebuild"
 	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/model"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/service"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/state"
 	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/util"
 	"github.com/spf13/cobra"
 )
@@ -24,11 +30,31 @@ func indexCmd() *cobra.Command {
 		Run: func(cmd *cobra.Command, args []string) {
 			inputDir, _ := cmd.Flags().GetString("input")
 			outputDir, _ := cmd.Flags().GetString("output")
+			incremental, _ := cmd.Flags().GetBool("incremental")
+			stateFile, _ := cmd.Flags().GetString("state")
+			baselineDir, _ := cmd.Flags().GetString("baseline")
 			if inputDir == "" || outputDir == "" {
 				slog.Error("input and output directory are required")
 				os.Exit(1)
 			}
-			slog.Info("generating index", "inputDir", inputDir, "outputDir", outputDir)
+			if stateFile == "" {
+				stateFile = filepath.Join(outputDir, "state.json")
+			}
+			if baselineDir == "" && incremental {
+				baselineDir = outputDir
+			}
+			slog.Info("generating index", "inputDir", inputDir, "outputDir", outputDir, "incremental", incremental, "baselineDir", baselineDir)
+
+			// load the previous index before it is overwritten, it is used to record the reproducibility history
+			var baseline *service.RegistryIndex
+			if baselineDir != "" {
+				baselineIndex, baselineErr := service.LoadRegistryIndex(baselineDir)
+				if baselineErr != nil {
+					slog.Error("failed to load baseline index", "dir", baselineDir, "error", baselineErr)
+					os.Exit(1)
+				}
+				baseline = baselineIndex
+			}
 
 			// search for maven-metadata.xml across all directories
 			files, filesErr := util.FindFiles(inputDir, "maven-metadata.xml")
@@ -37,13 +63,92 @@ func indexCmd() *cobra.Command {
 				os.Exit(1)
 			}
 
-			// process all files concurrently
-			depMetadata, projectMetadata := processFiles(files)
-			slog.Info("generated index", "projects", len(projectMetadata), "artifacts", len(depMetadata))
+			// collect all buildinfo files and the checksums of their source files
+			sources := collectBuildSources(inputDir, files)
+
+			// load the state of the previous run
+			var previous *state.Manifest
+			if incremental {
+				manifest, manifestErr := state.LoadManifest(stateFile)
+				if manifestErr != nil {
+					slog.Warn("failed to load state manifest, generating full index", "file", stateFile, "error", manifestErr)
+				} else {
+					previous = manifest
+				}
+			}
+
+			manifest := state.NewManifest()
+			modified := true
+			if previous == nil {
+				// process all files concurrently
+				results := processFiles(sources)
+				depMetadata, projectMetadata := mergeBuildResults(results)
+				slog.Info("generated index", "projects", len(projectMetadata), "artifacts", len(depMetadata))
+
+				// write data to filesystem
+				writeProjectIndexToFilesystem(outputDir, projectMetadata)
+				writeDependencyIndexToFilesystem(outputDir, depMetadata)
+				recordBuildResults(manifest, results)
+			} else {
+				// only process new or changed files
+				changed, removed := previous.Diff(sourceHashes(sources))
+				changedSources := slices.DeleteFunc(slices.Clone(sources), func(source *buildSource) bool {
+					_, found := slices.BinarySearch(changed, source.Key)
+					return !found
+				})
+				results := processFiles(changedSources)
+				slog.Info("generated incremental index", "changed", len(changed), "removed", len(removed), "processed", len(results))
+
+				// update affected files on the filesystem
+				updateIndexOnFilesystem(outputDir, previous, slices.Concat(changed, removed), results)
+
+				for key, build := range previous.Builds {
+					manifest.Builds[key] = build
+				}
+				// changed builds that failed to process are not recorded, so they are retried in the next run
+				for _, key := range slices.Concat(changed, removed) {
+					delete(manifest.Builds, key)
+				}
+				recordBuildResults(manifest, results)
+				modified = len(changed) > 0 || len(removed) > 0
+			}
 
-			// write data to filesystem
-			writeProjectIndexToFilesystem(outputDir, projectMetadata)
-			writeDependencyIndexToFilesystem(outputDir, depMetadata)
+			// aggregated files, generated from all index files of the output directory (an incremental run only holds the changed builds in memory)
+			registryIndex, err := service.LoadRegistryIndex(outputDir)
+			if err != nil {
+				slog.Error("failed to load generated index files", "error", err)
+				os.Exit(1)
+			}
+			if modified {
+				writeAggregatedIndexFiles(outputDir, registryIndex)
+			} else {
+				slog.Info("index unchanged, skipping aggregated files")
+			}
+
+			// reproducibility history, compared with the baseline
+			date := time.Now().UTC().Truncate(time.Second)
+			var histories map[string]*model.History
+			if baseline != nil {
+				histories = writeHistoryFiles(outputDir, baselineDir, baseline, registryIndex, date)
+			} else if histories, err = service.LoadHistories(outputDir); err != nil {
+				slog.Error("failed to load history files", "dir", outputDir, "error", err)
+				os.Exit(1)
+			}
+
+			// feeds of new rebuild results, generated from the histories
+			writeFeedFiles(outputDir, histories, registryIndex, date)
+
+			// persist state for the next incremental run
+			if err := manifest.Save(stateFile); err != nil {
+				slog.Error("failed to write state manifest", "file", stateFile, "error", err)
+				os.Exit(1)
+			}
+
+			// completion marker, written last to trigger the reload of running servers
+			if err := os.WriteFile(filepath.Join(outputDir, service.CompletedFile), []byte(date.Format(time.RFC3339)+"\n"), 0644); err != nil {
+				slog.Error("failed to write completion marker", "file", service.CompletedFile, "error", err)
+				os.Exit(1)
+			}
 
 			// write all metadata to file (disabled for now, this could very quickly use up the available github-pages bandwidth)
 			/*
@@ -58,13 +163,52 @@ func indexCmd() *cobra.Command {
 
 	cmd.Flags().StringP("input", "i", "", "Input Directory")
 	cmd.Flags().StringP("output", "o", "", "Output Directory")
+	cmd.Flags().Bool("incremental", false, "Only process files that changed since the last run (requires the state manifest of the previous run)")
+	cmd.Flags().String("state", "", "State manifest file (default: <output>/state.json)")
+	cmd.Flags().String("baseline", "", "Output directory of the previous run, used to record the reproducibility history (default: <output> in incremental mode)")
 
 	return cmd
 }
 
-func processFiles(files []string) (map[string]*model.Dependency, map[string]*model.Project) {
-	depMetadata := make(map[string]*model.Dependency)
-	projectMetadata := make(map[string]*model.Project)
+// buildSource is a .buildinfo file with its companion files
+type buildSource struct {
+	Key              string            // path of the .buildinfo file, relative to the input directory
+	Dir              string            // directory of the maven-metadata.xml file
+	BuildInfoFile    string            // path of the .buildinfo file
+	BuildCompareFile string            // path of the .buildcompare file
+	BuildSpecFile    string            // path of the .buildspec file
+	Hashes           map[string]string // source file (relative to the input directory) -> sha256 checksum
+	Latest           string            // latest version according to maven-metadata.xml
+	LatestRelease    string            // latest release version according to maven-metadata.xml
+	OverviewUrl      string            // link to the rebuild project
+}
+
+// buildResult is the index data generated from a single buildSource
+type buildResult struct {
+	Source       *buildSource
+	Version      string
+	Project      *model.Project
+	Dependencies map[string]*model.Dependency
+}
+
+// Outputs returns the version files generated for this build, relative to the output directory
+func (r *buildResult) Outputs() []string {
+	var outputs []string
+	for version := range r.Project.Versions {
+		outputs = append(outputs, filepath.Join(indexPath("project", r.Project.GroupID, r.Project.ArtifactID), version+".json"))
+	}
+	for _, dep := range r.Dependencies {
+		for version := range dep.Versions {
+			outputs = append(outputs, filepath.Join(indexPath("maven", dep.GroupID, dep.ArtifactID), version+".json"))
+		}
+	}
+	slices.Sort(outputs)
+
+	return outputs
+}
+
+func collectBuildSources(inputDir string, files []string) []*buildSource {
+	sources := make(map[string]*buildSource)
 	var wg sync.WaitGroup
 	var mu sync.Mutex
 	sem := make(chan struct{}, MaxConcurrency) // semaphore to limit concurrency
@@ -78,41 +222,39 @@ func processFiles(files []string) (map[string]*model.Dependency, map[string]*mod
 				<-sem // release semaphore
 			}()
 
-			data, projectData, err := processFile(file)
+			dirSources, err := collectDirectorySources(inputDir, file)
 			if err != nil {
 				slog.Error("failed to process file", "error", err)
 				return
 			}
 
-			// safely add metadata to map
+			// safely add sources to map, prefer the nearest maven-metadata.xml if directories are nested
 			mu.Lock()
-			for k, v := range data {
-				depMetadata[k] = v
-				slog.Debug("added to dependency data", "key", k, "versions", len(v.Versions))
-			}
-			for k, v := range projectData {
-				projectMetadata[k] = v
-				slog.Debug("added to project data", "key", k, "versions", len(v.Versions))
+			for _, source := range dirSources {
+				if existing, ok := sources[source.Key]; !ok || len(source.Dir) > len(existing.Dir) {
+					sources[source.Key] = source
+				}
 			}
 			mu.Unlock()
 		}(mvnMetadataFile)
 	}
 	wg.Wait()
 
-	return depMetadata, projectMetadata
+	result := slices.Collect(maps.Values(sources))
+	slices.SortFunc(result, func(a, b *buildSource) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+	return result
 }
 
-func processFile(mvnMetadataFile string) (map[string]*model.Dependency, map[string]*model.Project, error) {
-	result := make(map[string]*model.Dependency)     // individual artifact metadata
-	projectResult := make(map[string]*model.Project) // project metadata
-
+func collectDirectorySources(inputDir string, mvnMetadataFile string) ([]*buildSource, error) {
 	dir := filepath.Dir(mvnMetadataFile)
-	slog.Debug("found project", "path", mvnMetadataFile, "dir", filepath.Dir(mvnMetadataFile))
+	slog.Debug("found project", "path", mvnMetadataFile, "dir", dir)
 
 	// read maven-metadata.xml
 	mvnMetadata, mvnMetadataErr := util.ParseMavenMetadataFile(mvnMetadataFile)
 	if mvnMetadataErr != nil {
-		return nil, nil, errors.Join(errors.New("failed to parse maven-metadata.xml"), mvnMetadataErr)
+		return nil, errors.Join(errors.New("failed to parse maven-metadata.xml"), mvnMetadataErr)
 	}
 
 	// TODO: this will generate invalid links when using with other repos than reproducible-central
@@ -125,120 +267,509 @@ func processFile(mvnMetadataFile string) (map[string]*model.Dependency, map[stri
 
 	buildInfoFiles, err := util.FindFiles(dir, ".buildinfo")
 	if err != nil {
-		return nil, nil, errors.Join(errors.New("failed to find buildinfo files"), err)
+		return nil, errors.Join(errors.New("failed to find buildinfo files"), err)
 	}
-
 	slog.Debug("found buildinfo files", "count", len(buildInfoFiles))
+
+	var sources []*buildSource
 	for _, buildInfoFile := range buildInfoFiles {
-		slog.Debug("found buildinfo file", "path", buildInfoFile, "dir", filepath.Dir(buildInfoFile))
+		source := &buildSource{
+			Key:              relativePath(inputDir, buildInfoFile),
+			Dir:              dir,
+			BuildInfoFile:    buildInfoFile,
+			BuildCompareFile: strings.Replace(buildInfoFile, ".buildinfo", ".buildcompare", 1),
+			BuildSpecFile:    strings.Replace(buildInfoFile, ".buildinfo", ".buildspec", 1),
+			Hashes:           make(map[string]string),
+			Latest:           model.MaxVersion(slices.Concat(mvnMetadata.Versioning.Versions, []string{mvnMetadata.Versioning.Latest})...),
+			LatestRelease:    model.LatestVersion(slices.Concat(mvnMetadata.Versioning.Versions, []string{mvnMetadata.Versioning.Release}), model.IsReleaseVersion),
+			OverviewUrl:      overviewUrl,
+		}
 
-		buildInfo, buildInfoErr := jvmrebuild.ParseBuildInfo(buildInfoFile)
-		if buildInfoErr != nil {
-			slog.Error("failed to parse buildinfo file", "error", buildInfoErr, "file", buildInfoFile)
-			continue
+		for _, sourceFile := range []string{mvnMetadataFile, source.BuildInfoFile, source.BuildCompareFile, source.BuildSpecFile} {
+			hash, hashErr := state.HashFile(sourceFile)
+			if hashErr != nil {
+				if errors.Is(hashErr, os.ErrNotExist) {
+					continue
+				}
+				return nil, errors.Join(errors.New("failed to hash source file"), hashErr)
+			}
+			source.Hashes[relativePath(inputDir, sourceFile)] = hash
 		}
-		if buildInfo.SpecVersion != "" && buildInfo.SpecVersion != "0.1-SNAPSHOT" && buildInfo.SpecVersion != "1.0-SNAPSHOT" {
-			slog.Error("failed to find a supported artifactVersion in buildinfo file", "file", buildInfoFile, "version", buildInfo.SpecVersion)
-			continue
+
+		sources = append(sources, source)
+	}
+
+	return sources, nil
+}
+
+func processFiles(sources []*buildSource) []*buildResult {
+	var results []*buildResult
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	sem := make(chan struct{}, MaxConcurrency) // semaphore to limit concurrency
+	for _, src := range sources {
+		wg.Add(1)
+		sem <- struct{}{} // acquire semaphore
+
+		go func(source *buildSource) {
+			defer wg.Done()
+			defer func() {
+				<-sem // release semaphore
+			}()
+
+			result, err := processFile(source)
+			if err != nil {
+				slog.Error("failed to process file", "error", err, "file", source.BuildInfoFile)
+				return
+			}
+
+			// safely add result
+			mu.Lock()
+			results = append(results, result)
+			mu.Unlock()
+		}(src)
+	}
+	wg.Wait()
+
+	slices.SortFunc(results, func(a, b *buildResult) int {
+		return strings.Compare(a.Source.Key, b.Source.Key)
+	})
+	return results
+}
+
+func processFile(source *buildSource) (*buildResult, error) {
+	result := make(map[string]*model.Dependency) // individual artifact metadata
+	buildInfoFile := source.BuildInfoFile
+	slog.Debug("found buildinfo file", "path", buildInfoFile, "dir", filepath.Dir(buildInfoFile))
+
+	buildInfo, buildInfoErr := jvmrebuild.ParseBuildInfo(buildInfoFile)
+	if buildInfoErr != nil {
+		return nil, errors.Join(errors.New("failed to parse buildinfo file"), buildInfoErr)
+	}
+	if buildInfo.SpecVersion != "" && buildInfo.SpecVersion != "0.1-SNAPSHOT" && buildInfo.SpecVersion != "1.0-SNAPSHOT" {
+		return nil, fmt.Errorf("failed to find a supported artifactVersion in buildinfo file: %s", buildInfo.SpecVersion)
+	}
+
+	buildCompare, buildCompareErr := jvmrebuild.ParseBuildCompare(source.BuildCompareFile)
+	if buildCompareErr != nil {
+		return nil, errors.Join(errors.New("failed to parse buildcompare file"), buildCompareErr)
+	}
+
+	// the buildspec is optional, older rebuilds might not have one
+	var rebuildSpec *model.RebuildSpec
+	if buildSpec, buildSpecErr := jvmrebuild.ParseBuildSpec(source.BuildSpecFile); buildSpecErr == nil {
+		rebuildSpec = &model.RebuildSpec{
+			GitRepo: buildSpec.GitRepo,
+			GitTag:  buildSpec.GitTag,
+			Tool:    buildSpec.Tool,
+			JDK:     buildSpec.JDK,
+			Newline: buildSpec.Newline,
+			Command: buildSpec.Command,
 		}
+	} else if !errors.Is(buildSpecErr, os.ErrNotExist) {
+		slog.Warn("failed to parse buildspec file", "error", buildSpecErr, "file", source.BuildSpecFile)
+	}
+
+	artifactVersion := buildCompare.Version // buildInfo.Version is not always present, prefer buildCompare
+	versionData := model.Version{
+		Project:          buildInfo.Name,
+		SCMUri:           buildInfo.SourceSCMUri,
+		SCMTag:           buildInfo.SourceSCMTag,
+		BuildTool:        buildInfo.BuildTool,
+		BuildJavaVersion: buildInfo.JavaVersion,
+		BuildOSName:      buildInfo.OSName,
+		Reproducible:     buildCompare.Reproducible(),
+		RebuildSpec:      rebuildSpec,
+		FileStats:        model.FileStats{},
+	}
+	allArtifacts := make(map[string]model.File)
+	var allCoordinates []string
+
+	slog.Debug("parsed buildinfo and buildcompare file", "file", buildInfoFile, "version", artifactVersion)
+
+	// iterate over all outputs (look for key matching e.g. outputs.3.coordinates in buildInfo)
+	for _, output := range buildInfo.Outputs {
+		vd := versionData
+		vd.Files = make(map[string]model.File)
+		slog.Debug("found artifact", "coordinate", output.Coordinate)
 
-		buildCompareFile := strings.Replace(buildInfoFile, ".buildinfo", ".buildcompare", 1)
-		buildCompare, buildCompareErr := util.ParseFile(buildCompareFile)
-		if buildCompareErr != nil {
-			slog.Error("failed to parse buildinfo file", "error", buildCompareErr, "file", buildCompareFile)
+		groupId := strings.SplitN(output.Coordinate, ":", 2)[0]
+		artifactId := strings.SplitN(output.Coordinate, ":", 2)[1]
+		if groupId == "" || artifactId == "" {
+			slog.Warn("no group-id or artifact-id found for version", "version", artifactVersion, "coordinate", output.Coordinate)
 			continue
 		}
 
-		artifactVersion := buildCompare["version"] // buildInfo.Version is not always present, prefer buildCompare
-		versionData := model.Version{
-			Project:          buildInfo.Name,
-			SCMUri:           buildInfo.SourceSCMUri,
-			SCMTag:           buildInfo.SourceSCMTag,
-			BuildTool:        buildInfo.BuildTool,
-			BuildJavaVersion: buildInfo.JavaVersion,
-			BuildOSName:      buildInfo.OSName,
-			Reproducible:     buildCompare["ko"] == "0" && buildCompare["ok"] != "0",
-			FileStats:        model.FileStats{},
-		}
-		allArtifacts := make(map[string]model.File)
-		var allCoordinates []string
-
-		var reproducibleFiles []string
-		if rf, ok := buildCompare["okFiles"]; ok {
-			reproducibleFiles = strings.Split(rf, " ")
-		}
-		slog.Debug("parsed buildinfo and buildcompare file", "file", buildInfoFile, "version", artifactVersion)
-
-		// iterate over all outputs (look for key matching e.g. outputs.3.coordinates in buildInfo)
-		for _, output := range buildInfo.Outputs {
-			vd := versionData
-			vd.Files = make(map[string]model.File)
-			slog.Debug("found artifact", "coordinate", output.Coordinate)
-
-			groupId := strings.SplitN(output.Coordinate, ":", 2)[0]
-			artifactId := strings.SplitN(output.Coordinate, ":", 2)[1]
-			if groupId == "" || artifactId == "" {
-				slog.Warn("no group-id or artifact-id found for version", "version", artifactVersion, "coordinate", output.Coordinate)
-				continue
+		for name, file := range output.Files {
+			if strings.HasPrefix(name, artifactId+"-"+artifactVersion) {
+				vd.Files[name] = newFile(name, file, buildCompare)
+				allArtifacts[name] = vd.Files[name]
+			}
+		}
+		vd.SetModuleFileStats()
+
+		// create or append to result
+		if _, ok := result[output.Coordinate]; !ok {
+			result[output.Coordinate] = &model.Dependency{
+				RebuildProjectUrl: source.OverviewUrl,
+				GroupID:           groupId,
+				ArtifactID:        artifactId,
+				Versions:          map[string]*model.Version{artifactVersion: &vd},
+				Latest:            source.Latest,
+				LatestRelease:     source.LatestRelease,
 			}
+			allCoordinates = append(allCoordinates, output.Coordinate)
+		} else {
+			result[output.Coordinate].Versions[artifactVersion] = &vd
+		}
+	}
 
-			for name, file := range output.Files {
-				if strings.HasPrefix(name, artifactId+"-"+artifactVersion) {
-					reproducible := slices.Contains(reproducibleFiles, name)
-					vd.Files[name] = model.File{
-						Size:         file.Size,
-						Checksum:     file.Checksum,
-						Reproducible: reproducible,
-					}
-					allArtifacts[name] = vd.Files[name]
-				}
+	// set reproducible file count for the entire project
+	for rk := range result {
+		if result[rk].Versions[artifactVersion] == nil {
+			continue
+		}
+
+		result[rk].Versions[artifactVersion].SetTotalFileStats(allArtifacts)
+	}
+
+	// project metadata
+	versionData.Files = allArtifacts
+	versionData.SetTotalFileStats(allArtifacts)
+	versionData.SetModuleFileStats()
+
+	return &buildResult{
+		Source:  source,
+		Version: artifactVersion,
+		Project: &model.Project{
+			RebuildProjectUrl: source.OverviewUrl,
+			GroupID:           buildInfo.GroupID,
+			ArtifactID:        buildInfo.ArtifactID,
+			Modules:           allCoordinates,
+			Versions:          map[string]*model.Version{artifactVersion: &versionData},
+			Latest:            source.Latest,
+			LatestRelease:     source.LatestRelease,
+		},
+		Dependencies: result,
+	}, nil
+}
+
+// newFile creates the file metadata, non-reproducible files include the reason and diffoscope details
+func newFile(name string, file jvmrebuild.File, buildCompare jvmrebuild.BuildCompare) model.File {
+	result := model.File{
+		Size:         file.Size,
+		Checksum:     file.Checksum,
+		Reproducible: buildCompare.Status(name) == "ok",
+	}
+	if result.Reproducible {
+		return result
+	}
+
+	result.Reason = util.Ternary(buildCompare.Status(name) == "ko", model.FileReasonDifferent, model.FileReasonNotCompared)
+	if diffoscope, ok := buildCompare.DiffoscopeFor(name); ok {
+		result.DiffoscopeCommand = diffoscope.Command
+		result.DiffoscopeUrl = diffoscope.Link
+	}
+
+	return result
+}
+
+// mergeBuildResults combines the results of all builds into the project and artifact index
+func mergeBuildResults(results []*buildResult) (map[string]*model.Dependency, map[string]*model.Project) {
+	depMetadata := make(map[string]*model.Dependency)
+	projectMetadata := make(map[string]*model.Project)
+	for _, r := range results {
+		for k, v := range r.Dependencies {
+			if _, ok := depMetadata[k]; !ok {
+				depMetadata[k] = &model.Dependency{GroupID: v.GroupID, ArtifactID: v.ArtifactID, Versions: make(map[string]*model.Version)}
 			}
-			vd.SetModuleFileStats()
-
-			// create or append to result
-			if _, ok := result[output.Coordinate]; !ok {
-				result[output.Coordinate] = &model.Dependency{
-					RebuildProjectUrl: overviewUrl,
-					GroupID:           groupId,
-					ArtifactID:        artifactId,
-					Versions:          map[string]*model.Version{artifactVersion: &vd},
-					Latest:            mvnMetadata.Versioning.Latest,
-				}
-				allCoordinates = append(allCoordinates, output.Coordinate)
-			} else {
-				result[output.Coordinate].Versions[artifactVersion] = &vd
+			mergeDependency(depMetadata[k], v)
+			slog.Debug("added to dependency data", "key", k, "versions", len(v.Versions))
+		}
+
+		projectKey := r.Project.GroupID + ":" + r.Project.ArtifactID
+		if _, ok := projectMetadata[projectKey]; !ok {
+			projectMetadata[projectKey] = &model.Project{GroupID: r.Project.GroupID, ArtifactID: r.Project.ArtifactID, Versions: make(map[string]*model.Version)}
+		}
+		mergeProject(projectMetadata[projectKey], r.Project)
+		slog.Debug("added to project data", "key", projectKey, "versions", len(r.Project.Versions))
+	}
+
+	for _, v := range depMetadata {
+		v.UpdateVersionOrder()
+	}
+	for _, v := range projectMetadata {
+		v.UpdateVersionOrder()
+	}
+
+	return depMetadata, projectMetadata
+}
+
+func mergeProject(dst *model.Project, src *model.Project) {
+	if src.RebuildProjectUrl != "" {
+		dst.RebuildProjectUrl = src.RebuildProjectUrl
+	}
+	dst.Latest = model.MaxVersion(dst.Latest, src.Latest)
+	dst.LatestRelease = model.MaxVersion(dst.LatestRelease, src.LatestRelease)
+	for _, module := range src.Modules {
+		if !slices.Contains(dst.Modules, module) {
+			dst.Modules = append(dst.Modules, module)
+		}
+	}
+	for k, v := range src.Versions {
+		dst.Versions[k] = v
+	}
+}
+
+func mergeDependency(dst *model.Dependency, src *model.Dependency) {
+	if src.RebuildProjectUrl != "" {
+		dst.RebuildProjectUrl = src.RebuildProjectUrl
+	}
+	dst.Latest = model.MaxVersion(dst.Latest, src.Latest)
+	dst.LatestRelease = model.MaxVersion(dst.LatestRelease, src.LatestRelease)
+	for k, v := range src.Versions {
+		dst.Versions[k] = v
+	}
+}
+
+// recordBuildResults stores the source checksums and generated outputs of the given builds in the manifest
+//
+// Sources without a result are not recorded, they are processed again in the next incremental run.
+func recordBuildResults(manifest *state.Manifest, results []*buildResult) {
+	for _, r := range results {
+		manifest.Builds[r.Source.Key] = state.Build{
+			Hashes:  r.Source.Hashes,
+			Project: r.Project.GroupID + ":" + r.Project.ArtifactID,
+			Version: r.Version,
+			Modules: slices.Sorted(maps.Keys(r.Dependencies)),
+			Outputs: r.Outputs(),
+		}
+	}
+}
+
+// updateIndexOnFilesystem rewrites the files affected by the given builds and removes outputs that are no longer generated
+func updateIndexOnFilesystem(outputDir string, previous *state.Manifest, keys []string, results []*buildResult) {
+	depUpdates, projectUpdates := mergeBuildResults(results)
+
+	// collect versions and outputs of the previous run that need to be replaced
+	staleProjects := make(map[string][]string)
+	staleDependencies := make(map[string][]string)
+	staleOutputs := make(map[string]bool)
+	replaced := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		replaced[key] = true
+	}
+	keptModules := make(map[string]map[string]bool) // project -> modules of the builds that are not replaced
+	for key, build := range previous.Builds {
+		if replaced[key] || build.Project == "" {
+			continue
+		}
+		if keptModules[build.Project] == nil {
+			keptModules[build.Project] = make(map[string]bool)
+		}
+		for _, module := range build.Modules {
+			keptModules[build.Project][module] = true
+		}
+	}
+	for _, key := range keys {
+		build, ok := previous.Builds[key]
+		if !ok || build.Project == "" {
+			continue
+		}
+
+		staleProjects[build.Project] = append(staleProjects[build.Project], build.Version)
+		for _, module := range build.Modules {
+			staleDependencies[module] = append(staleDependencies[module], build.Version)
+		}
+		for _, output := range build.Outputs {
+			staleOutputs[output] = true
+		}
+	}
+
+	// projects
+	for key := range projectUpdates {
+		if _, ok := staleProjects[key]; !ok {
+			staleProjects[key] = nil
+		}
+	}
+	for key, versions := range staleProjects {
+		groupId, artifactId, _ := strings.Cut(key, ":")
+		indexFile := filepath.Join(outputDir, indexPath("project", groupId, artifactId), "index.json")
+
+		data, err := util.LoadFromDisk[model.Project](indexFile)
+		if err != nil || data.Versions == nil {
+			data = model.Project{GroupID: groupId, ArtifactID: artifactId, Versions: make(map[string]*model.Version)}
+		}
+		for _, version := range versions {
+			delete(data.Versions, version)
+		}
+		if len(versions) > 0 {
+			data.Modules = slices.DeleteFunc(data.Modules, func(module string) bool {
+				return !keptModules[key][module]
+			})
+		}
+		if update, ok := projectUpdates[key]; ok {
+			mergeProject(&data, update)
+		}
+		data.UpdateVersionOrder()
+
+		writeOrRemoveIndexFile(outputDir, indexFile, &data, len(data.Versions))
+	}
+
+	// artifacts
+	for key := range depUpdates {
+		if _, ok := staleDependencies[key]; !ok {
+			staleDependencies[key] = nil
+		}
+	}
+	for key, versions := range staleDependencies {
+		groupId, artifactId, _ := strings.Cut(key, ":")
+		indexFile := filepath.Join(outputDir, indexPath("maven", groupId, artifactId), "index.json")
+
+		data, err := util.LoadFromDisk[model.Dependency](indexFile)
+		if err != nil || data.Versions == nil {
+			data = model.Dependency{GroupID: groupId, ArtifactID: artifactId, Versions: make(map[string]*model.Version)}
+		}
+		for _, version := range versions {
+			delete(data.Versions, version)
+		}
+		if update, ok := depUpdates[key]; ok {
+			mergeDependency(&data, update)
+		}
+		data.UpdateVersionOrder()
+
+		writeOrRemoveIndexFile(outputDir, indexFile, &data, len(data.Versions))
+	}
+
+	// version files
+	for _, r := range results {
+		for _, output := range r.Outputs() {
+			delete(staleOutputs, output)
+		}
+		for version, versionMetadata := range r.Project.Versions {
+			writeIndexFile(filepath.Join(outputDir, indexPath("project", r.Project.GroupID, r.Project.ArtifactID), version+".json"), versionMetadata)
+		}
+		for _, dep := range r.Dependencies {
+			for version, versionMetadata := range dep.Versions {
+				writeIndexFile(filepath.Join(outputDir, indexPath("maven", dep.GroupID, dep.ArtifactID), version+".json"), versionMetadata)
 			}
 		}
+	}
 
-		// set reproducible file count for the entire project
-		for rk := range result {
-			if result[rk].Versions[artifactVersion] == nil {
+	// remove outputs whose source files disappeared
+	for output := range staleOutputs {
+		removeIndexFile(outputDir, filepath.Join(outputDir, output))
+	}
+}
+
+// writeAggregatedIndexFiles writes the files that cover the whole registry (search index, catalog and stats), they are computed in a single pass over the index
+func writeAggregatedIndexFiles(outputDir string, registryIndex *service.RegistryIndex) {
+	aggregates := service.NewAggregates(registryIndex)
+
+	writeIndexFile(filepath.Join(outputDir, service.SearchIndexFile), aggregates.SearchIndex)
+	slog.Info("generated search index", "entries", len(aggregates.SearchIndex.Entries))
+
+	catalog, err := service.WriteCatalog(filepath.Join(outputDir, service.CatalogDir), aggregates.Catalog)
+	if err != nil {
+		slog.Error("failed to write catalog", "error", err)
+		os.Exit(1)
+	}
+	slog.Info("generated catalog", "shards", len(catalog.Shards))
+
+	stats := aggregates.Stats
+	writeIndexFile(filepath.Join(outputDir, service.StatsFile), stats)
+	slog.Info("generated stats", "projects", stats.Projects, "versions", stats.Versions, "files", stats.Files, "reproducible", stats.Overall.VersionRate)
+}
+
+// writeHistoryFiles records the changes between the baseline and the current index in the history file of each project
+//
+// The histories are read from the baseline directory, unchanged histories are only written if the baseline is not the output directory.
+func writeHistoryFiles(outputDir string, baselineDir string, baseline *service.RegistryIndex, current *service.RegistryIndex, date time.Time) map[string]*model.History {
+	histories, err := service.LoadHistories(baselineDir)
+	if err != nil {
+		slog.Error("failed to load history files", "dir", baselineDir, "error", err)
+		os.Exit(1)
+	}
+
+	changed := service.RecordHistories(histories, baseline, current, date)
+	for key, history := range histories {
+		if filepath.Clean(baselineDir) == filepath.Clean(outputDir) {
+			if _, found := slices.BinarySearch(changed, key); !found {
 				continue
 			}
+		}
+		writeIndexFile(filepath.Join(outputDir, service.HistoryPath(history.GroupID, history.ArtifactID)), history)
+	}
+	slog.Info("recorded reproducibility history", "projects", len(histories), "changed", len(changed))
 
-			result[rk].Versions[artifactVersion].SetTotalFileStats(allArtifacts)
-		}
-
-		// append project metadata
-		versionData.Files = allArtifacts
-		versionData.SetTotalFileStats(allArtifacts)
-		versionData.SetModuleFileStats()
-		projectKey := buildInfo.GroupID + ":" + buildInfo.ArtifactID
-		if _, ok := projectResult[projectKey]; !ok {
-			projectResult[projectKey] = &model.Project{
-				RebuildProjectUrl: overviewUrl,
-				GroupID:           buildInfo.GroupID,
-				ArtifactID:        buildInfo.ArtifactID,
-				Modules:           allCoordinates,
-				Versions:          map[string]*model.Version{artifactVersion: &versionData},
-				Latest:            mvnMetadata.Versioning.Latest,
-			}
-		} else {
-			projectResult[projectKey].Versions[artifactVersion] = &versionData
+	return histories
+}
+
+// writeFeedFiles writes the feed index (used by the api) and the static atom feeds
+func writeFeedFiles(outputDir string, histories map[string]*model.History, current *service.RegistryIndex, date time.Time) {
+	feedIndex := service.NewFeedIndex(histories, current)
+	writeIndexFile(filepath.Join(outputDir, service.FeedIndexFile), feedIndex)
+
+	count, err := feed.WriteFeeds(outputDir, feedIndex, date)
+	if err != nil {
+		slog.Error("failed to write feeds", "error", err)
+		os.Exit(1)
+	}
+	slog.Info("generated feeds", "entries", len(feedIndex.Entries), "feeds", count)
+}
+
+func writeOrRemoveIndexFile(outputDir string, file string, data any, versionCount int) {
+	if versionCount == 0 {
+		removeIndexFile(outputDir, file)
+		return
+	}
+
+	writeIndexFile(file, data)
+}
+
+func writeIndexFile(file string, data any) {
+	slog.Debug("writing index file", "file", file)
+	if err := util.WriteToFile(file, data); err != nil {
+		slog.Error("failed to write artifact metadata to file", "error", err)
+		os.Exit(1)
+	}
+}
+
+// removeIndexFile deletes a generated file and all parent directories that became empty
+func removeIndexFile(outputDir string, file string) {
+	slog.Debug("removing index file", "file", file)
+	if err := os.Remove(file); err != nil && !errors.Is(err, os.ErrNotExist) {
+		slog.Error("failed to remove index file", "file", file, "error", err)
+		return
+	}
+
+	for dir := filepath.Dir(file); dir != filepath.Clean(outputDir) && strings.HasPrefix(dir, filepath.Clean(outputDir)); dir = filepath.Dir(dir) {
+		if err := os.Remove(dir); err != nil {
+			break // not empty
 		}
 	}
+}
 
-	return result, projectResult, nil
+// sourceHashes returns the checksums of all build sources, keyed by the source key
+func sourceHashes(sources []*buildSource) map[string]map[string]string {
+	hashes := make(map[string]map[string]string, len(sources))
+	for _, source := range sources {
+		hashes[source.Key] = source.Hashes
+	}
+	return hashes
+}
+
+// indexPath returns the directory of a project or artifact, relative to the output directory
+func indexPath(variant string, groupId string, artifactId string) string {
+	return filepath.Join(variant, strings.ReplaceAll(groupId, ".", "/"), strings.ReplaceAll(artifactId, ".", "/"))
+}
+
+func relativePath(baseDir string, file string) string {
+	rel, err := filepath.Rel(baseDir, file)
+	if err != nil {
+		return file
+	}
+	return filepath.ToSlash(rel)
 }
 
 func writeProjectIndexToFilesystem(outputDir string, data map[string]*model.Project) {
@@ -258,7 +789,7 @@ func writeProjectIndexToFilesystem(outputDir string, data map[string]*model.Proj
 			slog.Debug("writing artifact metadata", "group", data.GroupID, "artifact", data.ArtifactID)
 
 			// write artifact data
-			writeErr := util.WriteToFile(filepath.Join(outputDir, "project", strings.ReplaceAll(data.GroupID, ".", "/"), strings.ReplaceAll(data.ArtifactID, ".", "/"), "index.json"), data)
+			writeErr := util.WriteToFile(filepath.Join(outputDir, indexPath("project", data.GroupID, data.ArtifactID), "index.json"), data)
 			if writeErr != nil {
 				slog.Error("failed to write artifact metadata to file", "error", writeErr)
 				os.Exit(1)
@@ -266,7 +797,7 @@ func writeProjectIndexToFilesystem(outputDir string, data map[string]*model.Proj
 
 			// write version data
 			for version, versionMetadata := range data.Versions {
-				writeVerErr := util.WriteToFile(filepath.Join(outputDir, "project", strings.ReplaceAll(data.GroupID, ".", "/"), strings.ReplaceAll(data.ArtifactID, ".", "/"), version+".json"), versionMetadata)
+				writeVerErr := util.WriteToFile(filepath.Join(outputDir, indexPath("project", data.GroupID, data.ArtifactID), version+".json"), versionMetadata)
 				if writeVerErr != nil {
 					slog.Error("failed to write artifact metadata to file", "error", writeVerErr)
 					os.Exit(1)
@@ -287,7 +818,7 @@ func writeProjectIndexToFilesystem(outputDir string, data map[string]*model.Proj
 						IsError:       !latestVersion.Reproducible,
 						Style:         "flat",
 					}
-					writeErr = util.WriteToFile(filepath.Join(outputDir, "project", strings.ReplaceAll(data.GroupID, ".", "/"), strings.ReplaceAll(data.ArtifactID, ".", "/"), "badge.json"), badge)
+					writeErr = util.WriteToFile(filepath.Join(outputDir, indexPath("project", data.GroupID, data.ArtifactID), "badge.json"), badge)
 					if writeErr != nil {
 						slog.Error("failed to write artifact metadata to file", "error", writeErr)
 						os.Exit(1)
@@ -317,7 +848,7 @@ func writeDependencyIndexToFilesystem(outputDir string, data map[string]*model.D
 			slog.Debug("writing artifact metadata", "group", data.GroupID, "artifact", data.ArtifactID)
 
 			// write artifact data
-			writeErr := util.WriteToFile(filepath.Join(outputDir, "maven", strings.ReplaceAll(data.GroupID, ".", "/"), strings.ReplaceAll(data.ArtifactID, ".", "/"), "index.json"), data)
+			writeErr := util.WriteToFile(filepath.Join(outputDir, indexPath("maven", data.GroupID, data.ArtifactID), "index.json"), data)
 			if writeErr != nil {
 				slog.Error("failed to write artifact metadata to file", "error", writeErr)
 				os.Exit(1)
@@ -325,7 +856,7 @@ func writeDependencyIndexToFilesystem(outputDir string, data map[string]*model.D
 
 			// write version data
 			for version, versionMetadata := range data.Versions {
-				writeVerErr := util.WriteToFile(filepath.Join(outputDir, "maven", strings.ReplaceAll(data.GroupID, ".", "/"), strings.ReplaceAll(data.ArtifactID, ".", "/"), version+".json"), versionMetadata)
+				writeVerErr := util.WriteToFile(filepath.Join(outputDir, indexPath("maven", data.GroupID, data.ArtifactID), version+".json"), versionMetadata)
 				if writeVerErr != nil {
 					slog.Error("failed to write artifact metadata to file", "error", writeVerErr)
 					os.Exit(1)
@@ -346,7 +877,7 @@ func writeDependencyIndexToFilesystem(outputDir string, data map[string]*model.D
 						IsError:       !latestVersion.Reproducible,
 						Style:         "flat",
 					}
-					writeErr = util.WriteToFile(filepath.Join(outputDir, "maven", strings.ReplaceAll(data.GroupID, ".", "/"), strings.ReplaceAll(data.ArtifactID, ".", "/"), "badge.json"), badge)
+					writeErr = util.WriteToFile(filepath.Join(outputDir, indexPath("maven", data.GroupID, data.ArtifactID), "badge.json"), badge)
 					if writeErr != nil {
 						slog.Error("failed to write artifact metadata to file", "error", writeErr)
 						os.Exit(1)
@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/model"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/state"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/util"
+)
+
+const (
+	alphaKey = "com/example/alpha/alpha-1.0.buildinfo"
+	betaKey  = "com/example/beta/beta-1.0.buildinfo"
+)
+
+// testBuildResult creates the result of a single-module build of com.example:<artifactId>
+func testBuildResult(key string, artifactId string, hash string, reproducible bool) *buildResult {
+	coordinate := "com.example:" + artifactId
+	newVersion := func() *model.Version {
+		return &model.Version{Reproducible: reproducible, Files: map[string]model.File{artifactId + "-1.0.jar": {Reproducible: reproducible}}}
+	}
+
+	return &buildResult{
+		Source:  &buildSource{Key: key, Hashes: map[string]string{key: hash}},
+		Version: "1.0",
+		Project: &model.Project{GroupID: "com.example", ArtifactID: artifactId, Modules: []string{coordinate}, Versions: map[string]*model.Version{"1.0": newVersion()}, Latest: "1.0"},
+		Dependencies: map[string]*model.Dependency{
+			coordinate: {GroupID: "com.example", ArtifactID: artifactId, Versions: map[string]*model.Version{"1.0": newVersion()}, Latest: "1.0"},
+		},
+	}
+}
+
+// writeTestIndex generates the full index of the given builds, the modification time of all files is set to the past
+func writeTestIndex(t *testing.T, results []*buildResult) (string, *state.Manifest) {
+	t.Helper()
+	outputDir := t.TempDir()
+
+	depMetadata, projectMetadata := mergeBuildResults(results)
+	writeProjectIndexToFilesystem(outputDir, projectMetadata)
+	writeDependencyIndexToFilesystem(outputDir, depMetadata)
+
+	manifest := state.NewManifest()
+	recordBuildResults(manifest, results)
+
+	past := time.Now().Add(-time.Hour)
+	for file := range modTimes(t, outputDir) {
+		if err := os.Chtimes(filepath.Join(outputDir, file), past, past); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return outputDir, manifest
+}
+
+// modTimes returns the modification time of all files, keyed by the path relative to the directory
+func modTimes(t *testing.T, dir string) map[string]time.Time {
+	t.Helper()
+	result := make(map[string]time.Time)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		result[relativePath(dir, path)] = info.ModTime()
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+// touchedFiles returns the files that were written, added or removed
+func touchedFiles(before map[string]time.Time, after map[string]time.Time) []string {
+	var files []string
+	for file, modTime := range after {
+		if previous, ok := before[file]; !ok || !previous.Equal(modTime) {
+			files = append(files, file)
+		}
+	}
+	for file := range before {
+		if _, ok := after[file]; !ok {
+			files = append(files, file)
+		}
+	}
+	slices.Sort(files)
+	return files
+}
+
+func TestRecordBuildResults(t *testing.T) {
+	manifest := state.NewManifest()
+	result := testBuildResult(alphaKey, "alpha", "1", true)
+	recordBuildResults(manifest, []*buildResult{result})
+
+	want := state.Build{
+		Hashes:  map[string]string{alphaKey: "1"},
+		Project: "com.example:alpha",
+		Version: "1.0",
+		Modules: []string{"com.example:alpha"},
+		Outputs: []string{"maven/com/example/alpha/1.0.json", "project/com/example/alpha/1.0.json"},
+	}
+	if diff := cmp.Diff(want, manifest.Builds[alphaKey]); diff != "" {
+		t.Errorf("recordBuildResults() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestUpdateIndexOnFilesystem(t *testing.T) {
+	tests := []struct {
+		name    string
+		current []*buildResult // builds of the incremental run
+		want    []string       // files that are written or removed
+	}{
+		{
+			name:    "changed build",
+			current: []*buildResult{testBuildResult(alphaKey, "alpha", "2", false), testBuildResult(betaKey, "beta", "1", true)},
+			want: []string{
+				"maven/com/example/alpha/1.0.json",
+				"maven/com/example/alpha/index.json",
+				"project/com/example/alpha/1.0.json",
+				"project/com/example/alpha/index.json",
+			},
+		},
+		{
+			name:    "removed build",
+			current: []*buildResult{testBuildResult(betaKey, "beta", "1", true)},
+			want: []string{
+				"maven/com/example/alpha/1.0.json",
+				"maven/com/example/alpha/index.json",
+				"project/com/example/alpha/1.0.json",
+				"project/com/example/alpha/index.json",
+			},
+		},
+		{
+			name:    "unchanged builds",
+			current: []*buildResult{testBuildResult(alphaKey, "alpha", "1", true), testBuildResult(betaKey, "beta", "1", true)},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			outputDir, previous := writeTestIndex(t, []*buildResult{testBuildResult(alphaKey, "alpha", "1", true), testBuildResult(betaKey, "beta", "1", true)})
+			before := modTimes(t, outputDir)
+
+			// only the changed builds are processed, same as the incremental index command
+			hashes := make(map[string]map[string]string)
+			for _, r := range test.current {
+				hashes[r.Source.Key] = r.Source.Hashes
+			}
+			changed, removed := previous.Diff(hashes)
+			results := slices.DeleteFunc(slices.Clone(test.current), func(r *buildResult) bool {
+				return !slices.Contains(changed, r.Source.Key)
+			})
+			updateIndexOnFilesystem(outputDir, previous, slices.Concat(changed, removed), results)
+
+			after := modTimes(t, outputDir)
+			if diff := cmp.Diff(test.want, touchedFiles(before, after)); diff != "" {
+				t.Errorf("updateIndexOnFilesystem() touched files mismatch (-want +got):\n%s", diff)
+			}
+
+			// the index must match the current builds
+			for _, r := range test.current {
+				project, err := util.LoadFromDisk[model.Project](filepath.Join(outputDir, indexPath("project", r.Project.GroupID, r.Project.ArtifactID), "index.json"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if project.Versions["1.0"].Reproducible != r.Project.Versions["1.0"].Reproducible {
+					t.Errorf("expected %s:1.0 to be reproducible: %t", r.Project.ArtifactID, r.Project.Versions["1.0"].Reproducible)
+				}
+			}
+			if len(removed) > 0 {
+				if _, err := os.Stat(filepath.Join(outputDir, "project", "com", "example", "alpha")); !os.IsNotExist(err) {
+					t.Errorf("expected the directory of the removed build to be deleted, got %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateIndexOnFilesystemRemovesModules(t *testing.T) {
+	// alpha 1.0 had an additional module that is no longer published by alpha 2.0
+	legacy := testBuildResult(alphaKey, "alpha", "1", true)
+	legacy.Project.Modules = append(legacy.Project.Modules, "com.example:alpha-legacy")
+	legacy.Dependencies["com.example:alpha-legacy"] = &model.Dependency{GroupID: "com.example", ArtifactID: "alpha-legacy", Versions: map[string]*model.Version{"1.0": {Reproducible: true}}, Latest: "1.0"}
+	current := testBuildResult("com/example/alpha/alpha-2.0.buildinfo", "alpha", "1", true)
+	current.Version = "2.0"
+	current.Project.Versions = map[string]*model.Version{"2.0": current.Project.Versions["1.0"]}
+	current.Dependencies["com.example:alpha"].Versions = map[string]*model.Version{"2.0": current.Dependencies["com.example:alpha"].Versions["1.0"]}
+
+	outputDir, previous := writeTestIndex(t, []*buildResult{legacy, current})
+	updateIndexOnFilesystem(outputDir, previous, []string{alphaKey}, nil)
+
+	project, err := util.LoadFromDisk[model.Project](filepath.Join(outputDir, indexPath("project", "com.example", "alpha"), "index.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"com.example:alpha"}, project.Modules); diff != "" {
+		t.Errorf("updateIndexOnFilesystem() modules mismatch (-want +got):\n%s", diff)
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, indexPath("maven", "com.example", "alpha-legacy"))); !os.IsNotExist(err) {
+		t.Errorf("expected the directory of the removed module to be deleted, got %v", err)
+	}
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/service"
+	"github.com/spf13/cobra"
+)
+
+// addLookupFlags adds the flags to configure the index used for lookups (local index directory or remote index url)
+func addLookupFlags(cmd *cobra.Command) {
+	cmd.Flags().String("index-dir", "", "Index directory (for local index)")
+	cmd.Flags().String("index-url", "https://philippheuer.github.io/jvm-repo-rebuild-index", "Index URL (for remote index, used if index-dir is not set)")
+	cmd.Flags().String("registry", "repo.maven.apache.org/maven2", "Registry of the artifacts")
+	cmd.Flags().Int("concurrency", service.DefaultBatchConcurrency, "Maximum number of concurrent lookups")
+}
+
+// lookupVersionStatuses resolves the reproducibility status and version details of the coordinates, the results are keyed by coordinate
+func lookupVersionStatuses(cmd *cobra.Command, coordinates []string) map[string]service.VersionStatus {
+	lookupService, registry, concurrency := lookupConfig(cmd)
+
+	results := make(map[string]service.VersionStatus, len(coordinates))
+	for _, result := range service.LookupVersionStatuses(lookupService, registry, coordinates, concurrency) {
+		results[result.Result.Coordinate] = result
+	}
+	return results
+}
+
+func lookupConfig(cmd *cobra.Command) (lookupService service.DependencyLookupService, registry string, concurrency int) {
+	indexDir, _ := cmd.Flags().GetString("index-dir")
+	indexURL, _ := cmd.Flags().GetString("index-url")
+	registry, _ = cmd.Flags().GetString("registry")
+	concurrency, _ = cmd.Flags().GetInt("concurrency")
+	if indexDir != "" {
+		indexURL = ""
+	}
+
+	return service.NewDependencyLookupService(indexDir, indexURL, service.WithLocalRegistries()), registry, concurrency
+}
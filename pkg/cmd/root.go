@@ -29,6 +29,12 @@ func rootCmd() *cobra.Command {
 	cmd.AddCommand(versionCmd())
 	cmd.AddCommand(indexCmd())
 	cmd.AddCommand(serveCmd())
+	cmd.AddCommand(exportCmd())
+	cmd.AddCommand(sbomCmd())
+	cmd.AddCommand(checkCmd())
+	cmd.AddCommand(verifyCmd())
+	cmd.AddCommand(diffCmd())
+	cmd.AddCommand(siteCmd())
 
 	return cmd
 }
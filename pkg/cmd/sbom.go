@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"log/slog"
+	"os"
+	"slices"
+
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/sbom"
+	"github.com/spf13/cobra"
+)
+
+func sbomCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sbom",
+		Short: "add reproducibility information to sboms",
+		Run: func(cmd *cobra.Command, args []string) {
+			_ = cmd.Help()
+			os.Exit(0)
+		},
+	}
+
+	cmd.AddCommand(sbomEnrichCmd())
+
+	return cmd
+}
+
+func sbomEnrichCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "enrich",
+		Short: "add the reproducibility status and rebuild references to the maven components of a CycloneDX (json or xml) or SPDX (json) sbom",
+		Run: func(cmd *cobra.Command, args []string) {
+			inputFile, _ := cmd.Flags().GetString("input")
+			outputFile, _ := cmd.Flags().GetString("output")
+			if inputFile == "" {
+				slog.Error("input file is required")
+				os.Exit(1)
+			}
+			if outputFile == "" {
+				outputFile = inputFile
+			}
+
+			content, err := os.ReadFile(inputFile)
+			if err != nil {
+				slog.Error("failed to read sbom", "file", inputFile, "error", err)
+				os.Exit(1)
+			}
+			doc, err := sbom.Parse(content)
+			if err != nil {
+				slog.Error("failed to parse sbom", "file", inputFile, "error", err)
+				os.Exit(1)
+			}
+
+			purls := slices.Compact(slices.Sorted(slices.Values(doc.Purls())))
+			results := lookupVersionStatuses(cmd, purls)
+			for _, result := range results {
+				if result.Result.Error != "" {
+					slog.Warn("failed to lookup component", "purl", result.Result.Coordinate, "error", result.Result.Error)
+				}
+			}
+			count := doc.Enrich(results)
+
+			output, err := doc.Bytes()
+			if err != nil {
+				slog.Error("failed to encode sbom", "error", err)
+				os.Exit(1)
+			}
+			if err = os.WriteFile(outputFile, output, 0644); err != nil {
+				slog.Error("failed to write sbom", "file", outputFile, "error", err)
+				os.Exit(1)
+			}
+			slog.Info("enriched sbom", "file", outputFile, "components", count)
+		},
+	}
+
+	cmd.Flags().StringP("input", "i", "", "CycloneDX (json or xml) or SPDX (json) sbom")
+	cmd.Flags().StringP("output", "o", "", "Output file (default: overwrite the input file)")
+	addLookupFlags(cmd)
+
+	return cmd
+}
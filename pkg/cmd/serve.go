@@ -3,8 +3,11 @@ package cmd
 import (
 	"log/slog"
 	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/httpapi"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/service"
 	"github.com/spf13/cobra"
 )
 
@@ -17,13 +20,49 @@ func serveCmd() *cobra.Command {
 			port, _ := cmd.Flags().GetInt("port")
 			indexDir, _ := cmd.Flags().GetString("index-dir")
 			indexURL, _ := cmd.Flags().GetString("index-url")
+			batchConcurrency, _ := cmd.Flags().GetInt("batch-concurrency")
+			cacheBackend, _ := cmd.Flags().GetString("cache")
+			cacheDir, _ := cmd.Flags().GetString("cache-dir")
+			cacheSize, _ := cmd.Flags().GetInt("cache-size")
+			inMemory, _ := cmd.Flags().GetBool("in-memory")
 			if indexDir == "" && indexURL == "" {
 				slog.Error("Either index-dir or index-url must be set")
 				return
 			}
 
+			// cache
+			cacheConfig := service.CacheConfig{
+				TTL: map[service.CacheKind]time.Duration{},
+			}
+			cacheConfig.NegativeTTL, _ = cmd.Flags().GetDuration("cache-ttl-not-found")
+			cacheConfig.TTL[service.CacheKindIndex], _ = cmd.Flags().GetDuration("cache-ttl-index")
+			cacheConfig.TTL[service.CacheKindVersion], _ = cmd.Flags().GetDuration("cache-ttl-version")
+			cacheConfig.TTL[service.CacheKindPom], _ = cmd.Flags().GetDuration("cache-ttl-pom")
+			switch cacheBackend {
+			case "memory":
+				cacheConfig.Cache = service.NewMemoryCache(cacheSize)
+			case "disk":
+				cache, err := service.NewDiskCache(cacheDir, cacheSize)
+				if err != nil {
+					slog.Error("Error creating cache", "err", err)
+					os.Exit(1)
+				}
+				cacheConfig.Cache = cache
+			case "none":
+			default:
+				slog.Error("Unsupported cache backend, use memory, disk or none", "cache", cacheBackend)
+				os.Exit(1)
+			}
+
 			// start server
-			err := httpapi.Serve(port, indexDir, indexURL)
+			err := httpapi.Serve(httpapi.Config{
+				Port:             port,
+				IndexDir:         indexDir,
+				IndexURL:         indexURL,
+				BatchConcurrency: batchConcurrency,
+				Cache:            cacheConfig,
+				InMemory:         inMemory,
+			})
 			if err != nil {
 				slog.Error("Error starting server", "err", err)
 				os.Exit(1)
@@ -33,7 +72,16 @@ func serveCmd() *cobra.Command {
 
 	cmd.Flags().IntP("port", "p", 8080, "Port")
 	cmd.Flags().String("index-dir", "", "Index directory (for local index)")
+	cmd.Flags().Bool("in-memory", false, "Load the index directory into memory, the index is reloaded on changes or SIGHUP")
 	cmd.Flags().String("index-url", "https://philippheuer.github.io/jvm-repo-rebuild-index", "Index URL (as proxy for remote index)")
+	cmd.Flags().Int("batch-concurrency", service.DefaultBatchConcurrency, "Maximum number of concurrent lookups per batch request")
+	cmd.Flags().String("cache", "memory", "Cache for remote index files and poms (memory, disk or none)")
+	cmd.Flags().String("cache-dir", filepath.Join(os.TempDir(), "jvm-repo-rebuild-index-cache"), "Cache directory (for disk cache)")
+	cmd.Flags().Int("cache-size", service.DefaultMemoryCacheSize, "Maximum number of cached responses")
+	cmd.Flags().Duration("cache-ttl-index", service.DefaultCacheTTL[service.CacheKindIndex], "Cache duration of index.json files")
+	cmd.Flags().Duration("cache-ttl-version", service.DefaultCacheTTL[service.CacheKindVersion], "Cache duration of version files")
+	cmd.Flags().Duration("cache-ttl-pom", service.DefaultCacheTTL[service.CacheKindPom], "Cache duration of poms")
+	cmd.Flags().Duration("cache-ttl-not-found", service.DefaultNegativeCacheTTL, "Cache duration of not found responses (0 to disable)")
 
 	return cmd
 }
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/service"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/site"
+	"github.com/spf13/cobra"
+)
+
+func siteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "site",
+		Short: "generate a static html site to browse the index",
+		Run: func(cmd *cobra.Command, args []string) {
+			inputDir, _ := cmd.Flags().GetString("input")
+			outputDir, _ := cmd.Flags().GetString("output")
+			if inputDir == "" || outputDir == "" {
+				slog.Error("input and output directory are required")
+				os.Exit(1)
+			}
+			slog.Info("generating site", "inputDir", inputDir, "outputDir", outputDir)
+
+			registryIndex, err := service.LoadRegistryIndex(inputDir)
+			if err != nil {
+				slog.Error("failed to load index files", "dir", inputDir, "error", err)
+				os.Exit(1)
+			}
+
+			pages, err := site.Generate(outputDir, registryIndex)
+			if err != nil {
+				slog.Error("failed to generate site", "error", err)
+				os.Exit(1)
+			}
+			slog.Info("generated site", "projects", len(registryIndex.Projects()), "pages", pages)
+		},
+	}
+
+	cmd.Flags().StringP("input", "i", "", "Index Directory (output directory of the index command, e.g. index/mavencentral)")
+	cmd.Flags().StringP("output", "o", "", "Output Directory")
+
+	return cmd
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/verify"
+	"github.com/spf13/cobra"
+)
+
+func verifyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify [directory]",
+		Short: "compare the checksums of local artifacts (maven repository or directory of jars) with the rebuilt artifacts, exits with code 1 if a reproducible artifact differs",
+		Args:  cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			format, _ := cmd.Flags().GetString("format")
+			showUnknown, _ := cmd.Flags().GetBool("show-unknown")
+
+			dir := filepath.Join(userHomeDir(), ".m2", "repository")
+			if len(args) > 0 {
+				dir = args[0]
+			}
+
+			artifacts, err := verify.Scan(dir)
+			if err != nil {
+				slog.Error("failed to scan directory", "dir", dir, "error", err)
+				os.Exit(1)
+			}
+			coordinates := verify.Coordinates(artifacts)
+			slog.Info("verifying artifacts", "dir", dir, "artifacts", len(artifacts), "coordinates", len(coordinates))
+
+			report := verify.Compare(artifacts, lookupVersionStatuses(cmd, coordinates))
+			switch format {
+			case "text":
+				writeVerifyReport(report, showUnknown)
+			case "json":
+				encoder := json.NewEncoder(os.Stdout)
+				encoder.SetIndent("", "  ")
+				if err = encoder.Encode(report); err != nil {
+					slog.Error("failed to write report", "error", err)
+					os.Exit(1)
+				}
+			default:
+				slog.Error("unsupported format", "format", format)
+				os.Exit(1)
+			}
+
+			for _, result := range report.Results {
+				if result.Verdict == verify.VerdictMismatch && result.Reproducible {
+					os.Exit(1)
+				}
+			}
+		},
+	}
+
+	cmd.Flags().String("format", "text", "Report format (text, json)")
+	cmd.Flags().Bool("show-unknown", false, "Include artifacts without indexed checksum in the text report")
+	addLookupFlags(cmd)
+
+	return cmd
+}
+
+func writeVerifyReport(report verify.Report, showUnknown bool) {
+	for _, result := range report.Results {
+		if result.Verdict == verify.VerdictUnknown && !showUnknown {
+			continue
+		}
+
+		var details []string
+		if result.Verdict == verify.VerdictMismatch && !result.Reproducible {
+			details = append(details, "not reproducible, mismatch expected")
+		}
+		if result.Error != "" {
+			details = append(details, result.Error)
+		}
+		if result.Verdict == verify.VerdictMismatch && result.RebuildProjectUrl != "" {
+			details = append(details, result.RebuildProjectUrl)
+		}
+
+		line := fmt.Sprintf("%-8s  %s %s", strings.ToUpper(string(result.Verdict)), result.GAV.Coordinate(), result.Name())
+		if len(details) > 0 {
+			line += " (" + strings.Join(details, ", ") + ")"
+		}
+		fmt.Fprintln(os.Stdout, line)
+	}
+	fmt.Fprintf(os.Stdout, "\n%d matches, %d mismatches, %d unknown\n", report.Matches, report.Mismatches, report.Unknown)
+}
+
+func userHomeDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "."
+	}
+	return home
+}
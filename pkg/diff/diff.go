@@ -0,0 +1,355 @@
+package diff
+
+import (
+	"errors"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/model"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/service"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/util"
+)
+
+// Change is the kind of change of a project, version or file
+type Change string
+
+const (
+	ChangeAdded   Change = "added"
+	ChangeRemoved Change = "removed"
+	ChangeChanged Change = "changed"
+)
+
+// Flip is a change of the reproducibility of a version
+type Flip string
+
+const (
+	FlipImproved  Flip = "improved"  // the version became reproducible
+	FlipRegressed Flip = "regressed" // the version is no longer reproducible
+)
+
+var ErrUnsupportedSnapshot = errors.New("unsupported snapshot, expected an index directory or a catalog")
+var ErrMixedSnapshots = errors.New("an index directory can not be compared with a catalog, the index contains projects and the catalog artifacts")
+
+// Snapshot contains the projects of an index directory or the artifacts of a catalog
+type Snapshot struct {
+	Projects   map[string]*model.Dependency // keyed by groupId:artifactId
+	LatestOnly bool                         // catalogs only contain the latest version of each artifact
+}
+
+// Report contains all changes between two snapshots, sorted by coordinate
+type Report struct {
+	Old        string          `json:"old"`
+	New        string          `json:"new"`
+	LatestOnly bool            `json:"latest_only,omitempty"` // both snapshots are catalogs, a new latest version is reported as changed version
+	Summary    Summary         `json:"summary"`
+	Projects   []ProjectChange `json:"projects"`
+}
+
+// Summary contains the number of changes
+type Summary struct {
+	ProjectsAdded   int `json:"projects_added"`
+	ProjectsRemoved int `json:"projects_removed"`
+	ProjectsChanged int `json:"projects_changed"`
+	VersionsAdded   int `json:"versions_added"`
+	VersionsRemoved int `json:"versions_removed"`
+	VersionsChanged int `json:"versions_changed"`
+	Improved        int `json:"improved"`
+	Regressed       int `json:"regressed"`
+}
+
+// ProjectChange is an added, removed or changed project (or artifact for catalogs)
+type ProjectChange struct {
+	GroupID    string          `json:"group_id"`
+	ArtifactID string          `json:"artifact_id"`
+	Change     Change          `json:"change"`
+	Versions   []VersionChange `json:"versions"`
+}
+
+// Coordinate returns the project coordinate (groupId:artifactId)
+func (p ProjectChange) Coordinate() string {
+	return p.GroupID + ":" + p.ArtifactID
+}
+
+// VersionChange is an added, removed or changed version
+type VersionChange struct {
+	Version         string                      `json:"version"`
+	PreviousVersion string                      `json:"previous_version,omitempty"` // previous latest version, only set when comparing catalogs
+	Change          Change                      `json:"change"`
+	OldStatus       model.ReproducibilityStatus `json:"old_status,omitempty"`
+	NewStatus       model.ReproducibilityStatus `json:"new_status,omitempty"`
+	Flip            Flip                        `json:"flip,omitempty"`
+	Files           []FileChange                `json:"files,omitempty"`
+}
+
+// FileChange is an added, removed or changed file of a version, changed files differ in their reproducibility or checksum
+type FileChange struct {
+	Name            string `json:"name"`
+	Change          Change `json:"change"`
+	OldReproducible *bool  `json:"old_reproducible,omitempty"`
+	NewReproducible *bool  `json:"new_reproducible,omitempty"`
+	ChecksumChanged bool   `json:"checksum_changed,omitempty"`
+}
+
+// LoadSnapshot reads the projects of an index directory (<dir>/project/...) or the artifacts of a catalog (catalog directory, catalog index.json or a single .tsv.gz shard)
+func LoadSnapshot(path string) (Snapshot, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	if info.IsDir() {
+		if _, statErr := os.Stat(filepath.Join(path, "project")); statErr == nil {
+			index, loadErr := service.LoadRegistryIndex(path)
+			if loadErr != nil {
+				return Snapshot{}, loadErr
+			}
+			snapshot := Snapshot{Projects: make(map[string]*model.Dependency)}
+			for _, project := range index.Projects() {
+				snapshot.Projects[project.GroupID+":"+project.ArtifactID] = project
+			}
+			return snapshot, nil
+		}
+
+		for _, file := range []string{filepath.Join(path, service.CatalogDir, "index.json"), filepath.Join(path, "index.json")} {
+			if _, statErr := os.Stat(file); statErr == nil {
+				return loadCatalog(file)
+			}
+		}
+		return Snapshot{}, ErrUnsupportedSnapshot
+	}
+
+	switch {
+	case strings.HasSuffix(path, ".tsv.gz"):
+		return loadCatalogShards(path)
+	case strings.HasSuffix(path, ".json"):
+		return loadCatalog(path)
+	}
+	return Snapshot{}, ErrUnsupportedSnapshot
+}
+
+// loadCatalog reads all shards of the catalog index
+func loadCatalog(file string) (Snapshot, error) {
+	catalog, err := util.LoadFromDisk[model.Catalog](file)
+	if err != nil {
+		return Snapshot{}, errors.Join(ErrUnsupportedSnapshot, err)
+	}
+
+	var shards []string
+	for _, shard := range catalog.Shards {
+		shards = append(shards, filepath.Join(filepath.Dir(file), shard.File))
+	}
+	return loadCatalogShards(shards...)
+}
+
+func loadCatalogShards(files ...string) (Snapshot, error) {
+	snapshot := Snapshot{Projects: make(map[string]*model.Dependency), LatestOnly: true}
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			return Snapshot{}, err
+		}
+		entries, err := service.ReadCatalogShard(f)
+		f.Close()
+		if err != nil {
+			return Snapshot{}, err
+		}
+
+		for _, entry := range entries {
+			snapshot.Projects[entry.GroupID+":"+entry.ArtifactID] = &model.Dependency{
+				GroupID:    entry.GroupID,
+				ArtifactID: entry.ArtifactID,
+				Versions:   map[string]*model.Version{entry.Version: {Reproducible: entry.Reproducible}},
+			}
+		}
+	}
+	return snapshot, nil
+}
+
+// Compare returns all changes between the previous and current snapshot
+//
+// If both snapshots are catalogs, a new latest version of an artifact is reported as changed version instead of a removed and an added version.
+// Returns ErrMixedSnapshots if only one of the snapshots is a catalog.
+func Compare(previous Snapshot, current Snapshot) (Report, error) {
+	if previous.LatestOnly != current.LatestOnly {
+		return Report{}, ErrMixedSnapshots
+	}
+	report := Report{Projects: []ProjectChange{}, LatestOnly: current.LatestOnly}
+
+	keys := slices.Sorted(maps.Keys(previous.Projects))
+	for key := range current.Projects {
+		if _, ok := previous.Projects[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		oldProject, newProject := previous.Projects[key], current.Projects[key]
+
+		var change ProjectChange
+		switch {
+		case oldProject == nil:
+			change = ProjectChange{GroupID: newProject.GroupID, ArtifactID: newProject.ArtifactID, Change: ChangeAdded}
+		case newProject == nil:
+			change = ProjectChange{GroupID: oldProject.GroupID, ArtifactID: oldProject.ArtifactID, Change: ChangeRemoved}
+		default:
+			change = ProjectChange{GroupID: newProject.GroupID, ArtifactID: newProject.ArtifactID, Change: ChangeChanged}
+		}
+
+		if report.LatestOnly && change.Change == ChangeChanged {
+			change.Versions = compareLatest(oldProject.Versions, newProject.Versions)
+		} else {
+			change.Versions = compareVersions(versionsOf(oldProject), versionsOf(newProject))
+		}
+		if len(change.Versions) == 0 {
+			continue
+		}
+		switch change.Change {
+		case ChangeAdded:
+			report.Summary.ProjectsAdded++
+		case ChangeRemoved:
+			report.Summary.ProjectsRemoved++
+		case ChangeChanged:
+			report.Summary.ProjectsChanged++
+		}
+		for _, v := range change.Versions {
+			switch v.Change {
+			case ChangeAdded:
+				report.Summary.VersionsAdded++
+			case ChangeRemoved:
+				report.Summary.VersionsRemoved++
+			case ChangeChanged:
+				report.Summary.VersionsChanged++
+			}
+			switch v.Flip {
+			case FlipImproved:
+				report.Summary.Improved++
+			case FlipRegressed:
+				report.Summary.Regressed++
+			}
+		}
+		report.Projects = append(report.Projects, change)
+	}
+
+	return report, nil
+}
+
+func versionsOf(project *model.Dependency) map[string]*model.Version {
+	if project == nil {
+		return nil
+	}
+	return project.Versions
+}
+
+// compareVersions returns the changed versions, ordered by version
+func compareVersions(previous map[string]*model.Version, current map[string]*model.Version) []VersionChange {
+	versions := slices.Collect(maps.Keys(previous))
+	for version := range current {
+		if _, ok := previous[version]; !ok {
+			versions = append(versions, version)
+		}
+	}
+	model.SortVersions(versions)
+
+	var changes []VersionChange
+	for _, version := range versions {
+		oldVersion, newVersion := previous[version], current[version]
+		switch {
+		case oldVersion == nil:
+			changes = append(changes, VersionChange{Version: version, Change: ChangeAdded, NewStatus: status(newVersion)})
+		case newVersion == nil:
+			changes = append(changes, VersionChange{Version: version, Change: ChangeRemoved, OldStatus: status(oldVersion)})
+		default:
+			change := VersionChange{
+				Version:   version,
+				Change:    ChangeChanged,
+				OldStatus: status(oldVersion),
+				NewStatus: status(newVersion),
+				Files:     compareFiles(oldVersion.Files, newVersion.Files),
+			}
+			if oldVersion.Reproducible != newVersion.Reproducible {
+				change.Flip = util.Ternary(newVersion.Reproducible, FlipImproved, FlipRegressed)
+			}
+			if change.OldStatus == change.NewStatus && change.Flip == "" && len(change.Files) == 0 {
+				continue
+			}
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// compareLatest compares the latest versions of catalog artifacts, a different latest version is reported as changed version
+func compareLatest(previous map[string]*model.Version, current map[string]*model.Version) []VersionChange {
+	if len(previous) != 1 || len(current) != 1 {
+		return compareVersions(previous, current)
+	}
+	oldName, oldVersion := latestVersion(previous)
+	newName, newVersion := latestVersion(current)
+	if oldName == newName {
+		return compareVersions(previous, current)
+	}
+
+	change := VersionChange{
+		Version:         newName,
+		PreviousVersion: oldName,
+		Change:          ChangeChanged,
+		OldStatus:       status(oldVersion),
+		NewStatus:       status(newVersion),
+	}
+	if oldVersion.Reproducible != newVersion.Reproducible {
+		change.Flip = util.Ternary(newVersion.Reproducible, FlipImproved, FlipRegressed)
+	}
+	return []VersionChange{change}
+}
+
+// latestVersion returns the only version of a catalog artifact
+func latestVersion(versions map[string]*model.Version) (string, *model.Version) {
+	for name, version := range versions {
+		return name, version
+	}
+	return "", nil
+}
+
+// compareFiles returns the added, removed and changed files, ordered by name
+func compareFiles(previous map[string]model.File, current map[string]model.File) []FileChange {
+	names := slices.Collect(maps.Keys(previous))
+	for name := range current {
+		if _, ok := previous[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	var changes []FileChange
+	for _, name := range names {
+		oldFile, oldOk := previous[name]
+		newFile, newOk := current[name]
+		switch {
+		case !oldOk:
+			changes = append(changes, FileChange{Name: name, Change: ChangeAdded, NewReproducible: &newFile.Reproducible})
+		case !newOk:
+			changes = append(changes, FileChange{Name: name, Change: ChangeRemoved, OldReproducible: &oldFile.Reproducible})
+		case oldFile.Reproducible != newFile.Reproducible || oldFile.Checksum != newFile.Checksum:
+			changes = append(changes, FileChange{
+				Name:            name,
+				Change:          ChangeChanged,
+				OldReproducible: &oldFile.Reproducible,
+				NewReproducible: &newFile.Reproducible,
+				ChecksumChanged: oldFile.Checksum != newFile.Checksum,
+			})
+		}
+	}
+	return changes
+}
+
+// status returns the reproducibility status of a version, catalog versions only contain the reproducible flag
+func status(v *model.Version) model.ReproducibilityStatus {
+	if reproducible, nonReproducible := v.FileStats.Counts(); reproducible+nonReproducible > 0 {
+		return v.Status()
+	}
+	return util.Ternary(v.Reproducible, model.StatusReproducible, model.StatusNotReproducible)
+}
@@ -0,0 +1,132 @@
+package diff
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/model"
+)
+
+func testVersion(reproducible bool, files map[string]model.File) *model.Version {
+	v := &model.Version{Reproducible: reproducible, Files: files}
+	v.SetModuleFileStats()
+	return v
+}
+
+func TestCompare(t *testing.T) {
+	previous := Snapshot{Projects: map[string]*model.Dependency{
+		"com.example:foo": {GroupID: "com.example", ArtifactID: "foo", Versions: map[string]*model.Version{
+			"1.0": testVersion(true, map[string]model.File{"foo-1.0.jar": {Checksum: "a", Reproducible: true}}),
+			"1.1": testVersion(true, map[string]model.File{"foo-1.1.jar": {Checksum: "b", Reproducible: true}, "foo-1.1.pom": {Checksum: "c", Reproducible: true}}),
+		}},
+		"com.example:old": {GroupID: "com.example", ArtifactID: "old", Versions: map[string]*model.Version{
+			"1.0": testVersion(false, map[string]model.File{"old-1.0.jar": {Reproducible: false}}),
+		}},
+	}}
+	current := Snapshot{Projects: map[string]*model.Dependency{
+		"com.example:foo": {GroupID: "com.example", ArtifactID: "foo", Versions: map[string]*model.Version{
+			"1.0": testVersion(true, map[string]model.File{"foo-1.0.jar": {Checksum: "a", Reproducible: true}}),
+			"1.1": testVersion(false, map[string]model.File{"foo-1.1.jar": {Checksum: "x", Reproducible: false}, "foo-1.1.pom": {Checksum: "c", Reproducible: true}}),
+			"1.2": testVersion(true, map[string]model.File{"foo-1.2.jar": {Checksum: "d", Reproducible: true}}),
+		}},
+		"com.example:new": {GroupID: "com.example", ArtifactID: "new", Versions: map[string]*model.Version{
+			"1.0": testVersion(true, map[string]model.File{"new-1.0.jar": {Reproducible: true}}),
+		}},
+	}}
+
+	report, err := Compare(previous, current)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantSummary := Summary{ProjectsAdded: 1, ProjectsRemoved: 1, ProjectsChanged: 1, VersionsAdded: 2, VersionsRemoved: 1, VersionsChanged: 1, Regressed: 1}
+	if diff := cmp.Diff(wantSummary, report.Summary); diff != "" {
+		t.Errorf("summary mismatch (-want +got):\n%s", diff)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteText(&buf, report); err != nil {
+		t.Fatal(err)
+	}
+	want := `~ com.example:foo
+    ~ 1.1 reproducible -> partially-reproducible (regressed)
+        ~ foo-1.1.jar reproducible -> not reproducible, checksum changed
+    + 1.2 reproducible
++ com.example:new (added)
+    + 1.0 reproducible
+- com.example:old (removed)
+    - 1.0 not-reproducible
+
+Projects: 1 added, 1 removed, 1 changed
+Versions: 2 added, 1 removed, 1 changed
+Reproducibility: 0 improved, 1 regressed
+`
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("WriteText() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestCompareIdentical(t *testing.T) {
+	snapshot := Snapshot{Projects: map[string]*model.Dependency{
+		"com.example:foo": {GroupID: "com.example", ArtifactID: "foo", Versions: map[string]*model.Version{"1.0": {Reproducible: true}}},
+	}}
+
+	report, err := Compare(snapshot, snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Projects) != 0 || report.Summary != (Summary{}) {
+		t.Errorf("expected no changes, got %+v", report)
+	}
+}
+
+func TestCompareCatalogs(t *testing.T) {
+	previous := Snapshot{LatestOnly: true, Projects: map[string]*model.Dependency{
+		"com.example:foo": {GroupID: "com.example", ArtifactID: "foo", Versions: map[string]*model.Version{"1.0": {Reproducible: true}}},
+		"com.example:bar": {GroupID: "com.example", ArtifactID: "bar", Versions: map[string]*model.Version{"2.0": {Reproducible: true}}},
+	}}
+	current := Snapshot{LatestOnly: true, Projects: map[string]*model.Dependency{
+		"com.example:foo": {GroupID: "com.example", ArtifactID: "foo", Versions: map[string]*model.Version{"1.1": {Reproducible: false}}},
+		"com.example:bar": {GroupID: "com.example", ArtifactID: "bar", Versions: map[string]*model.Version{"2.0": {Reproducible: true}}},
+	}}
+
+	report, err := Compare(previous, current)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantSummary := Summary{ProjectsChanged: 1, VersionsChanged: 1, Regressed: 1}
+	if diff := cmp.Diff(wantSummary, report.Summary); diff != "" {
+		t.Errorf("summary mismatch (-want +got):\n%s", diff)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteText(&buf, report); err != nil {
+		t.Fatal(err)
+	}
+	want := `~ com.example:foo
+    ~ 1.0 -> 1.1 reproducible -> not-reproducible (regressed)
+
+Projects: 0 added, 0 removed, 1 changed
+Versions: 0 added, 0 removed, 1 changed
+Reproducibility: 0 improved, 1 regressed
+Catalogs only contain the latest version of each artifact, new latest versions are reported as changed versions.
+`
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("WriteText() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestCompareMixedSnapshots(t *testing.T) {
+	index := Snapshot{Projects: map[string]*model.Dependency{}}
+	catalog := Snapshot{LatestOnly: true, Projects: map[string]*model.Dependency{}}
+
+	if _, err := Compare(index, catalog); !errors.Is(err, ErrMixedSnapshots) {
+		t.Errorf("expected ErrMixedSnapshots, got %v", err)
+	}
+	if _, err := Compare(catalog, index); !errors.Is(err, ErrMixedSnapshots) {
+		t.Errorf("expected ErrMixedSnapshots, got %v", err)
+	}
+}
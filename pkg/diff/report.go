@@ -0,0 +1,203 @@
+package diff
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Format is the output format of a diff report
+type Format string
+
+const (
+	FormatText     Format = "text"     // one line per change
+	FormatJSON     Format = "json"     // the report as json
+	FormatMarkdown Format = "markdown" // summary and tables, e.g. for the weekly report
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported report format")
+
+// WriteReport writes the report in the requested format
+func WriteReport(w io.Writer, format Format, report Report) error {
+	switch format {
+	case FormatText:
+		return WriteText(w, report)
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	case FormatMarkdown:
+		return WriteMarkdown(w, report)
+	}
+	return fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+}
+
+// changeSymbols are used as line prefix in the text report
+var changeSymbols = map[Change]string{
+	ChangeAdded:   "+",
+	ChangeRemoved: "-",
+	ChangeChanged: "~",
+}
+
+// WriteText writes one line per changed project, version and file followed by a summary
+func WriteText(w io.Writer, report Report) error {
+	var sb strings.Builder
+	for _, project := range report.Projects {
+		fmt.Fprintf(&sb, "%s %s", changeSymbols[project.Change], project.Coordinate())
+		if project.Change != ChangeChanged {
+			fmt.Fprintf(&sb, " (%s)", project.Change)
+		}
+		sb.WriteString("\n")
+
+		for _, version := range project.Versions {
+			fmt.Fprintf(&sb, "    %s %s %s", changeSymbols[version.Change], version.versionText(" -> "), version.statusText(" -> "))
+			if version.Flip != "" {
+				fmt.Fprintf(&sb, " (%s)", version.Flip)
+			}
+			sb.WriteString("\n")
+
+			for _, file := range version.Files {
+				fmt.Fprintf(&sb, "        %s %s %s\n", changeSymbols[file.Change], file.Name, file.text(" -> "))
+			}
+		}
+	}
+
+	s := report.Summary
+	if len(report.Projects) > 0 {
+		sb.WriteString("\n")
+	}
+	fmt.Fprintf(&sb, "Projects: %d added, %d removed, %d changed\n", s.ProjectsAdded, s.ProjectsRemoved, s.ProjectsChanged)
+	fmt.Fprintf(&sb, "Versions: %d added, %d removed, %d changed\n", s.VersionsAdded, s.VersionsRemoved, s.VersionsChanged)
+	fmt.Fprintf(&sb, "Reproducibility: %d improved, %d regressed\n", s.Improved, s.Regressed)
+	if report.LatestOnly {
+		sb.WriteString("Catalogs only contain the latest version of each artifact, new latest versions are reported as changed versions.\n")
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// WriteMarkdown writes a summary table, the reproducibility flips and a section for added, removed and changed projects
+func WriteMarkdown(w io.Writer, report Report) error {
+	var sb strings.Builder
+	s := report.Summary
+
+	sb.WriteString("# Index Diff\n\n")
+	if report.Old != "" || report.New != "" {
+		fmt.Fprintf(&sb, "Comparing `%s` with `%s`.\n\n", report.Old, report.New)
+	}
+	sb.WriteString("|          | Added | Removed | Changed |\n")
+	sb.WriteString("|----------|------:|--------:|--------:|\n")
+	fmt.Fprintf(&sb, "| Projects | %d | %d | %d |\n", s.ProjectsAdded, s.ProjectsRemoved, s.ProjectsChanged)
+	fmt.Fprintf(&sb, "| Versions | %d | %d | %d |\n\n", s.VersionsAdded, s.VersionsRemoved, s.VersionsChanged)
+	fmt.Fprintf(&sb, "**Reproducibility**: %d improved, %d regressed\n", s.Improved, s.Regressed)
+	if report.LatestOnly {
+		sb.WriteString("\nCatalogs only contain the latest version of each artifact, new latest versions are reported as changed versions.\n")
+	}
+
+	// flips
+	if s.Improved+s.Regressed > 0 {
+		sb.WriteString("\n## Reproducibility Flips\n\n")
+		sb.WriteString("| Project | Version | Status | Flip |\n")
+		sb.WriteString("|---------|---------|--------|------|\n")
+		for _, project := range report.Projects {
+			for _, version := range project.Versions {
+				if version.Flip != "" {
+					fmt.Fprintf(&sb, "| `%s` | %s | %s | %s |\n", project.Coordinate(), version.versionText(" → "), version.statusText(" → "), version.Flip)
+				}
+			}
+		}
+	}
+
+	// projects
+	for _, change := range []Change{ChangeAdded, ChangeRemoved} {
+		var lines []string
+		for _, project := range report.Projects {
+			if project.Change != change {
+				continue
+			}
+			var versions []string
+			for _, version := range project.Versions {
+				versions = append(versions, version.Version)
+			}
+			lines = append(lines, fmt.Sprintf("- `%s` (%s)\n", project.Coordinate(), strings.Join(versions, ", ")))
+		}
+		if len(lines) > 0 {
+			fmt.Fprintf(&sb, "\n## %s Projects\n\n%s", strings.ToUpper(string(change[:1]))+string(change[1:]), strings.Join(lines, ""))
+		}
+	}
+
+	var changed []ProjectChange
+	for _, project := range report.Projects {
+		if project.Change == ChangeChanged {
+			changed = append(changed, project)
+		}
+	}
+	if len(changed) > 0 {
+		sb.WriteString("\n## Changed Projects\n")
+		for _, project := range changed {
+			fmt.Fprintf(&sb, "\n### `%s`\n\n", project.Coordinate())
+			sb.WriteString("| Version | Change | Status | Files |\n")
+			sb.WriteString("|---------|--------|--------|-------|\n")
+			for _, version := range project.Versions {
+				var files []string
+				for _, file := range version.Files {
+					files = append(files, fmt.Sprintf("%s `%s` %s", file.Change, file.Name, file.text(" → ")))
+				}
+				fmt.Fprintf(&sb, "| %s | %s | %s | %s |\n", version.versionText(" → "), version.Change, version.statusText(" → "), strings.Join(files, "<br>"))
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// versionText returns the version, or the previous and new latest version of catalog artifacts
+func (v VersionChange) versionText(arrow string) string {
+	if v.PreviousVersion != "" {
+		return v.PreviousVersion + arrow + v.Version
+	}
+	return v.Version
+}
+
+// statusText returns the status of the version, or the status transition of changed versions
+func (v VersionChange) statusText(arrow string) string {
+	switch {
+	case v.Change == ChangeAdded:
+		return string(v.NewStatus)
+	case v.Change == ChangeRemoved:
+		return string(v.OldStatus)
+	case v.OldStatus != v.NewStatus:
+		return string(v.OldStatus) + arrow + string(v.NewStatus)
+	}
+	return string(v.NewStatus)
+}
+
+// text describes the file change, e.g. reproducible -> not reproducible, checksum changed
+func (f FileChange) text(arrow string) string {
+	reproducibility := func(reproducible *bool) string {
+		if reproducible != nil && *reproducible {
+			return "reproducible"
+		}
+		return "not reproducible"
+	}
+
+	switch f.Change {
+	case ChangeAdded:
+		return reproducibility(f.NewReproducible)
+	case ChangeRemoved:
+		return reproducibility(f.OldReproducible)
+	}
+
+	var parts []string
+	if reproducibility(f.OldReproducible) != reproducibility(f.NewReproducible) {
+		parts = append(parts, reproducibility(f.OldReproducible)+arrow+reproducibility(f.NewReproducible))
+	}
+	if f.ChecksumChanged {
+		parts = append(parts, "checksum changed")
+	}
+	return strings.Join(parts, ", ")
+}
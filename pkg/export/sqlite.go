@@ -0,0 +1,190 @@
+package export
+
+import (
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/model"
+	_ "modernc.org/sqlite" // database/sql driver
+)
+
+var ErrSQLiteExport = errors.New("failed to export sqlite database")
+
+const sqliteSchema = `
+CREATE TABLE projects (
+	id                  INTEGER PRIMARY KEY,
+	group_id            TEXT NOT NULL,
+	artifact_id         TEXT NOT NULL,
+	rebuild_project_url TEXT,
+	latest              TEXT,
+	latest_release      TEXT,
+	latest_reproducible TEXT,
+	UNIQUE (group_id, artifact_id)
+);
+
+CREATE TABLE modules (
+	id                  INTEGER PRIMARY KEY,
+	project_id          INTEGER REFERENCES projects (id),
+	group_id            TEXT NOT NULL,
+	artifact_id         TEXT NOT NULL,
+	latest              TEXT,
+	latest_release      TEXT,
+	latest_reproducible TEXT,
+	UNIQUE (group_id, artifact_id)
+);
+
+CREATE TABLE versions (
+	id                     INTEGER PRIMARY KEY,
+	project_id             INTEGER NOT NULL REFERENCES projects (id),
+	version                TEXT NOT NULL,
+	project_name           TEXT,
+	scm_uri                TEXT,
+	scm_tag                TEXT,
+	build_tool             TEXT,
+	build_java_version     TEXT,
+	build_os_name          TEXT,
+	reproducible           INTEGER NOT NULL,
+	reproducible_files     INTEGER NOT NULL,
+	non_reproducible_files INTEGER NOT NULL,
+	UNIQUE (project_id, version)
+);
+
+CREATE TABLE files (
+	id                 INTEGER PRIMARY KEY,
+	version_id         INTEGER NOT NULL REFERENCES versions (id),
+	module_id          INTEGER REFERENCES modules (id),
+	name               TEXT NOT NULL,
+	size               TEXT,
+	checksum           TEXT,
+	reproducible       INTEGER NOT NULL,
+	reason             TEXT,
+	diffoscope_command TEXT,
+	diffoscope_url     TEXT
+);
+
+CREATE INDEX modules_project_idx ON modules (project_id);
+CREATE INDEX versions_version_idx ON versions (version);
+CREATE INDEX files_version_idx ON files (version_id);
+CREATE INDEX files_module_idx ON files (module_id);
+`
+
+// WriteSQLite writes the projects and artifacts into a new sqlite database, an existing file is replaced once the export is complete
+//
+// Tables: projects, modules (maven artifacts of a project), versions (rebuilds of a project) and files (rebuilt files of a version and module)
+func WriteSQLite(file string, projects map[string]*model.Project, dependencies map[string]*model.Dependency) error {
+	if err := os.MkdirAll(filepath.Dir(file), os.ModePerm); err != nil {
+		return errors.Join(ErrSQLiteExport, err)
+	}
+	tmpFile := file + ".tmp"
+	_ = os.Remove(tmpFile)
+
+	db, err := sql.Open("sqlite", tmpFile)
+	if err != nil {
+		return errors.Join(ErrSQLiteExport, err)
+	}
+	err = writeSQLiteTables(db, projects, dependencies)
+	if closeErr := db.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmpFile)
+		return errors.Join(ErrSQLiteExport, err)
+	}
+
+	if err = os.Rename(tmpFile, file); err != nil {
+		return errors.Join(ErrSQLiteExport, err)
+	}
+	return nil
+}
+
+func writeSQLiteTables(db *sql.DB, projects map[string]*model.Project, dependencies map[string]*model.Dependency) error {
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	projectIds := make(map[string]int64)      // groupId:artifactId -> id
+	moduleProjects := make(map[string]string) // module groupId:artifactId -> project groupId:artifactId
+	for _, key := range sortedKeys(projects) {
+		p := projects[key]
+		res, insertErr := tx.Exec(`INSERT INTO projects (group_id, artifact_id, rebuild_project_url, latest, latest_release, latest_reproducible) VALUES (?, ?, ?, ?, ?, ?)`,
+			p.GroupID, p.ArtifactID, p.RebuildProjectUrl, p.Latest, p.LatestRelease, p.LatestReproducible)
+		if insertErr != nil {
+			return insertErr
+		}
+		projectIds[key], _ = res.LastInsertId()
+		for _, module := range p.Modules {
+			if _, ok := moduleProjects[module]; !ok {
+				moduleProjects[module] = key
+			}
+		}
+	}
+
+	moduleIds := make(map[string]int64)   // groupId:artifactId -> id
+	moduleFiles := make(map[string]int64) // project groupId:artifactId + version + file name -> module id
+	for _, key := range sortedKeys(dependencies) {
+		d := dependencies[key]
+		var projectId sql.NullInt64
+		if id, ok := projectIds[moduleProjects[key]]; ok {
+			projectId = sql.NullInt64{Int64: id, Valid: true}
+		}
+		res, insertErr := tx.Exec(`INSERT INTO modules (project_id, group_id, artifact_id, latest, latest_release, latest_reproducible) VALUES (?, ?, ?, ?, ?, ?)`,
+			projectId, d.GroupID, d.ArtifactID, d.Latest, d.LatestRelease, d.LatestReproducible)
+		if insertErr != nil {
+			return insertErr
+		}
+		moduleIds[key], _ = res.LastInsertId()
+		for version, v := range d.Versions {
+			for name := range v.Files {
+				moduleFiles[moduleProjects[key]+"/"+version+"/"+name] = moduleIds[key]
+			}
+		}
+	}
+
+	for _, key := range sortedKeys(projects) {
+		p := projects[key]
+		for _, version := range p.OrderedVersions {
+			v := p.Versions[version]
+			if v == nil {
+				continue
+			}
+			res, insertErr := tx.Exec(`INSERT INTO versions (project_id, version, project_name, scm_uri, scm_tag, build_tool, build_java_version, build_os_name, reproducible, reproducible_files, non_reproducible_files) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+				projectIds[key], version, v.Project, v.SCMUri, v.SCMTag, v.BuildTool, v.BuildJavaVersion, v.BuildOSName, v.Reproducible, v.FileStats.TotalReproducibleFiles, v.FileStats.TotalNonReproducibleFiles)
+			if insertErr != nil {
+				return insertErr
+			}
+			versionId, _ := res.LastInsertId()
+
+			for _, name := range sortedKeys(v.Files) {
+				f := v.Files[name]
+				var moduleId sql.NullInt64
+				if id, ok := moduleFiles[key+"/"+version+"/"+name]; ok {
+					moduleId = sql.NullInt64{Int64: id, Valid: true}
+				}
+				if _, insertErr = tx.Exec(`INSERT INTO files (version_id, module_id, name, size, checksum, reproducible, reason, diffoscope_command, diffoscope_url) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+					versionId, moduleId, name, f.Size, f.Checksum, f.Reproducible, f.Reason, f.DiffoscopeCommand, f.DiffoscopeUrl); insertErr != nil {
+					return insertErr
+				}
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,50 @@
+package export
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/model"
+)
+
+func TestWriteSQLite(t *testing.T) {
+	version := &model.Version{
+		BuildTool:    "maven",
+		Reproducible: false,
+		Files: map[string]model.File{
+			"foo-core-1.0.jar": {Reproducible: true},
+			"foo-core-1.0.pom": {Reproducible: false, Reason: model.FileReasonDifferent},
+		},
+	}
+	projects := map[string]*model.Project{
+		"com.example:foo": {GroupID: "com.example", ArtifactID: "foo", Modules: []string{"com.example:foo-core"}, Versions: map[string]*model.Version{"1.0": version}, OrderedVersions: []string{"1.0"}},
+	}
+	dependencies := map[string]*model.Dependency{
+		"com.example:foo-core": {GroupID: "com.example", ArtifactID: "foo-core", Versions: map[string]*model.Version{"1.0": version}, OrderedVersions: []string{"1.0"}},
+	}
+
+	file := filepath.Join(t.TempDir(), "index.sqlite")
+	if err := WriteSQLite(file, projects, dependencies); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := sql.Open("sqlite", file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var count int
+	err = db.QueryRow(`SELECT count(*) FROM files f
+		JOIN versions v ON v.id = f.version_id
+		JOIN modules m ON m.id = f.module_id
+		JOIN projects p ON p.id = m.project_id AND p.id = v.project_id
+		WHERE p.artifact_id = 'foo' AND m.artifact_id = 'foo-core' AND v.version = '1.0' AND v.build_tool = 'maven' AND f.reproducible = 0`).Scan(&count)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Errorf("expected 1 non-reproducible file, got %d", count)
+	}
+}
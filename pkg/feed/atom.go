@@ -0,0 +1,144 @@
+package feed
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/model"
+)
+
+// ContentType is the media type of atom feeds
+const ContentType = "application/atom+xml"
+
+// idPrefix is used for the feed and entry ids, the index has no canonical url
+const idPrefix = "urn:jvm-repo-rebuild-index:"
+
+// Meta describes a feed, Updated is used if the feed has no entries
+type Meta struct {
+	ID      string // unique id, e.g. project:<groupId>:<artifactId>
+	Title   string
+	Link    string // optional url of the feed itself
+	Updated time.Time
+}
+
+// Feed is an atom feed (RFC 4287)
+type Feed struct {
+	XMLName   xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+	ID        string   `xml:"id"`
+	Title     string   `xml:"title"`
+	Updated   string   `xml:"updated"`
+	Generator string   `xml:"generator"`
+	Links     []Link   `xml:"link"`
+	Author    *Person  `xml:"author,omitempty"`
+	Entries   []Entry  `xml:"entry"`
+}
+
+type Link struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type Person struct {
+	Name string `xml:"name"`
+}
+
+type Category struct {
+	Term string `xml:"term,attr"`
+}
+
+type Entry struct {
+	ID         string     `xml:"id"`
+	Title      string     `xml:"title"`
+	Updated    string     `xml:"updated"`
+	Links      []Link     `xml:"link"`
+	Categories []Category `xml:"category"`
+	Summary    string     `xml:"summary"`
+}
+
+// New creates a feed of the given entries, the feed is updated at the date of the newest entry
+func New(meta Meta, entries []model.FeedEntry) Feed {
+	feed := Feed{
+		ID:        idPrefix + "feed:" + meta.ID,
+		Title:     meta.Title,
+		Generator: "jvm-repo-rebuild-index",
+		Author:    &Person{Name: "jvm-repo-rebuild"},
+		Entries:   []Entry{},
+	}
+	if meta.Link != "" {
+		feed.Links = append(feed.Links, Link{Href: meta.Link, Rel: "self", Type: ContentType})
+	}
+
+	var updated time.Time
+	for _, entry := range entries {
+		if entry.Date.After(updated) {
+			updated = entry.Date
+		}
+		feed.Entries = append(feed.Entries, newEntry(entry))
+	}
+	if len(entries) == 0 {
+		updated = meta.Updated
+	}
+	feed.Updated = updated.UTC().Format(time.RFC3339)
+
+	return feed
+}
+
+func newEntry(entry model.FeedEntry) Entry {
+	coordinate := entry.Coordinate() + ":" + entry.Version
+	result := Entry{
+		ID:         fmt.Sprintf("%s%s:%s:%d", idPrefix, coordinate, entry.Event, entry.Date.Unix()),
+		Title:      Title(entry),
+		Updated:    entry.Date.UTC().Format(time.RFC3339),
+		Categories: []Category{{Term: string(entry.Status)}},
+		Summary:    Summary(entry),
+	}
+	if entry.RebuildProjectUrl != "" {
+		result.Links = append(result.Links, Link{Href: entry.RebuildProjectUrl, Rel: "alternate", Type: "text/html"})
+	}
+	if entry.Regression {
+		result.Categories = append(result.Categories, Category{Term: "regression"})
+	}
+	return result
+}
+
+// Title returns the entry title, e.g. com.example:foo:1.0 is reproducible
+func Title(entry model.FeedEntry) string {
+	coordinate := entry.Coordinate() + ":" + entry.Version
+	switch {
+	case entry.Event == model.HistoryEventFirstSeen:
+		return fmt.Sprintf("%s is %s", coordinate, entry.Status)
+	case entry.Regression:
+		return fmt.Sprintf("%s regressed to %s", coordinate, entry.Status)
+	}
+	return fmt.Sprintf("%s changed to %s", coordinate, entry.Status)
+}
+
+// Summary describes the rebuild result, e.g. Rebuilt com.example:foo:1.0: reproducible, 3 of 3 files are reproducible.
+func Summary(entry model.FeedEntry) string {
+	reproducible, nonReproducible := entry.FileStats.Counts()
+	summary := fmt.Sprintf("Rebuilt %s:%s: %s", entry.Coordinate(), entry.Version, entry.Status)
+	if entry.Event == model.HistoryEventStatusChanged {
+		summary = fmt.Sprintf("Rebuilt %s:%s: %s (previously %s)", entry.Coordinate(), entry.Version, entry.Status, entry.PreviousStatus)
+	}
+	if reproducible+nonReproducible > 0 {
+		summary += fmt.Sprintf(", %d of %d files are reproducible", reproducible, reproducible+nonReproducible)
+	}
+	return summary + "."
+}
+
+// Write writes the feed as xml document
+func Write(w io.Writer, feed Feed) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(feed); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,86 @@
+package feed
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/model"
+)
+
+// Dir is the directory of the static feeds, relative to the registry directory of the index
+const Dir = "feed"
+
+// MaxEntries limits the entries of each static feed to the newest rebuild results
+const MaxEntries = 100
+
+// GlobalPath returns the path of the feed of all projects, relative to the registry directory
+func GlobalPath() string {
+	return filepath.Join(Dir, "atom.xml")
+}
+
+// GroupPath returns the path of the feed of all projects of a groupId, relative to the registry directory
+func GroupPath(groupId string) string {
+	return filepath.Join(Dir, "group", filepath.FromSlash(strings.ReplaceAll(groupId, ".", "/")), "atom.xml")
+}
+
+// ProjectPath returns the path of the feed of a single project, relative to the registry directory
+func ProjectPath(groupId string, artifactId string) string {
+	gav := model.GAV{GroupId: groupId, ArtifactId: artifactId}
+	return filepath.Join(Dir, "project", filepath.FromSlash(gav.Path(true)), "atom.xml")
+}
+
+// WriteFeeds replaces the static feeds of the registry directory with the global feed, a feed per groupId and a feed per project
+func WriteFeeds(dir string, index *model.FeedIndex, date time.Time) (int, error) {
+	if err := os.RemoveAll(filepath.Join(dir, Dir)); err != nil {
+		return 0, errors.Join(errors.New("failed to remove previous feeds"), err)
+	}
+
+	groups := make(map[string][]model.FeedEntry)
+	projects := make(map[string][]model.FeedEntry)
+	for _, entry := range index.Entries {
+		groups[entry.GroupID] = append(groups[entry.GroupID], entry)
+		projects[entry.Coordinate()] = append(projects[entry.Coordinate()], entry)
+	}
+
+	count := 0
+	write := func(path string, meta Meta, entries []model.FeedEntry) error {
+		meta.Updated = date
+		if err := writeFeed(filepath.Join(dir, path), New(meta, entries[:min(len(entries), MaxEntries)])); err != nil {
+			return err
+		}
+		count++
+		return nil
+	}
+
+	if err := write(GlobalPath(), Meta{ID: "all", Title: "Reproducible Builds: all projects"}, index.Entries); err != nil {
+		return count, err
+	}
+	for groupId, entries := range groups {
+		if err := write(GroupPath(groupId), Meta{ID: "group:" + groupId, Title: "Reproducible Builds: " + groupId}, entries); err != nil {
+			return count, err
+		}
+	}
+	for coordinate, entries := range projects {
+		groupId, artifactId, _ := strings.Cut(coordinate, ":")
+		if err := write(ProjectPath(groupId, artifactId), Meta{ID: "project:" + coordinate, Title: "Reproducible Builds: " + coordinate}, entries); err != nil {
+			return count, err
+		}
+	}
+
+	return count, nil
+}
+
+func writeFeed(file string, feed Feed) error {
+	var buf bytes.Buffer
+	if err := Write(&buf, feed); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), os.ModePerm); err != nil {
+		return err
+	}
+	return os.WriteFile(file, buf.Bytes(), 0644)
+}
@@ -0,0 +1,55 @@
+package feed
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/model"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/util"
+)
+
+func TestWriteFeeds(t *testing.T) {
+	index := &model.FeedIndex{Entries: []model.FeedEntry{
+		{GroupID: "com.example", ArtifactID: "foo", Version: "1.0", Date: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), Event: model.HistoryEventStatusChanged, Status: model.StatusPartiallyReproducible, PreviousStatus: model.StatusReproducible, Regression: true, FileStats: model.FileStats{ModuleReproducibleFiles: 1, ModuleNonReproducibleFiles: 1}, RebuildProjectUrl: "https://example.com/foo"},
+		{GroupID: "com.example", ArtifactID: "bar", Version: "2.0", Date: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Event: model.HistoryEventFirstSeen, Status: model.StatusReproducible},
+	}}
+	dir := t.TempDir()
+
+	count, err := WriteFeeds(dir, index, time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("WriteFeeds() error = %v", err)
+	}
+	if count != 4 {
+		t.Errorf("expected 4 feeds (global, group, 2 projects), got %d", count)
+	}
+
+	feed, err := util.LoadXMLFromDisk[Feed](filepath.Join(dir, GroupPath("com.example")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var titles []string
+	for _, entry := range feed.Entries {
+		titles = append(titles, entry.Title)
+	}
+	want := []string{"com.example:foo:1.0 regressed to partially-reproducible", "com.example:bar:2.0 is reproducible"}
+	if diff := cmp.Diff(want, titles); diff != "" {
+		t.Errorf("entry titles mismatch (-want +got):\n%s", diff)
+	}
+	if feed.Updated != "2024-01-02T00:00:00Z" {
+		t.Errorf("expected the date of the newest entry, got %s", feed.Updated)
+	}
+	if summary := feed.Entries[0].Summary; summary != "Rebuilt com.example:foo:1.0: partially-reproducible (previously reproducible), 1 of 2 files are reproducible." {
+		t.Errorf("unexpected summary %q", summary)
+	}
+
+	// feeds of removed projects are deleted
+	if _, err = WriteFeeds(dir, &model.FeedIndex{}, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = os.Stat(filepath.Join(dir, ProjectPath("com.example", "foo"))); !os.IsNotExist(err) {
+		t.Errorf("expected the project feed to be removed, got %v", err)
+	}
+}
@@ -1,9 +1,11 @@
 package httpapi
 
 import (
+	"context"
 	"embed"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
@@ -12,7 +14,21 @@ import (
 )
 
 type handlers struct {
-	lookupService service.DependencyLookupService
+	lookupService    service.DependencyLookupService
+	batchConcurrency int
+}
+
+// Config contains the server configuration
+type Config struct {
+	Port     int
+	IndexDir string
+	IndexURL string
+	// BatchConcurrency limits the concurrent lookups of a single batch request
+	BatchConcurrency int
+	// Cache configures the caching of remote index files and poms
+	Cache service.CacheConfig
+	// InMemory loads the IndexDir into memory, the index is reloaded on changes or SIGHUP
+	InMemory bool
 }
 
 var ErrStartingServer = errors.New("error starting server")
@@ -20,7 +36,7 @@ var ErrStartingServer = errors.New("error starting server")
 //go:embed public
 var staticAssets embed.FS
 
-func Serve(port int, indexDir string, indexURL string) error {
+func Serve(config Config) error {
 	// config
 	e := echo.New()
 	e.HideBanner = true
@@ -42,7 +58,24 @@ func Serve(port int, indexDir string, indexURL string) error {
 
 	// services
 	handlerStruct := handlers{
-		lookupService: service.NewDependencyLookupService(indexDir, indexURL),
+		lookupService:    service.NewCachedDependencyLookupService(config.IndexDir, config.IndexURL, config.Cache),
+		batchConcurrency: config.BatchConcurrency,
+	}
+	if config.InMemory {
+		if config.IndexDir == "" {
+			return errors.Join(ErrStartingServer, errors.New("in-memory index requires an index directory"))
+		}
+
+		index, err := service.NewReloadableIndex(config.IndexDir)
+		if err != nil {
+			return errors.Join(ErrStartingServer, err)
+		}
+		go func() {
+			if watchErr := index.Watch(context.Background()); watchErr != nil {
+				slog.Error("Failed to watch index directory, use SIGHUP to reload the index", "err", watchErr)
+			}
+		}()
+		handlerStruct.lookupService = service.NewInMemoryDependencyLookupService(index, config.Cache)
 	}
 
 	// handlers
@@ -56,14 +89,34 @@ func Serve(port int, indexDir string, indexURL string) error {
 	e.GET("/v1/badge/reproducible-dependencies/maven/:registry/:coordinate/:version", handlerStruct.transitiveDependencyBadgeHandler)
 	e.GET("/v1/badge/reproducible-dependencies/maven/:coordinate/:version", handlerStruct.transitiveDependencyBadgeHandler)
 
+	e.GET("/v1/badge/reproducible/stats", handlerStruct.statsBadgeHandler)
+	e.GET("/v1/badge/reproducible/stats.svg", handlerStruct.statsBadgeHandler)
+
 	e.GET("/v1/badge/reproducible/project/:coordinate/:version", handlerStruct.projectBadgeHandler)
 	e.GET("/v1/badge/reproducible/project/:registry/:coordinate/:version", handlerStruct.projectBadgeHandler)
 
 	e.GET("/v1/redirect/reproducible/maven/:coordinate/:version", handlerStruct.redirectHandler)
 	e.GET("/v1/redirect/reproducible/maven/:registry/:coordinate/:version", handlerStruct.redirectHandler)
 
+	e.GET("/v1/maven/:coordinate/:version", handlerStruct.dependencyQueryHandler)
+	e.GET("/v1/maven/:registry/:coordinate/:version", handlerStruct.dependencyQueryHandler)
+
+	e.GET("/v1/project/:coordinate/:version", handlerStruct.projectQueryHandler)
+	e.GET("/v1/project/:registry/:coordinate/:version", handlerStruct.projectQueryHandler)
+
+	e.GET("/v1/history/project/:coordinate", handlerStruct.historyHandler)
+	e.GET("/v1/history/project/:registry/:coordinate", handlerStruct.historyHandler)
+
+	e.POST("/v1/lookup/batch", handlerStruct.batchLookupHandler)
+
+	e.GET("/v1/search", handlerStruct.searchHandler)
+
+	e.GET("/v1/feed", handlerStruct.feedHandler)
+
+	e.GET("/v1/stats", handlerStruct.statsHandler)
+
 	// start
-	startErr := e.Start(fmt.Sprintf(":%d", port))
+	startErr := e.Start(fmt.Sprintf(":%d", config.Port))
 	if startErr != nil {
 		if startErr.Error() == "http: Server closed" {
 			return nil
@@ -1,25 +1,26 @@
 package httpapi
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"net/url"
-	"slices"
+	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/badge"
 	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/model"
 	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/service"
-	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/sonatype"
 	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/util"
 )
 
 func (h handlers) projectBadgeHandler(c echo.Context) error {
 	registry := c.Param("registry")
 	coordinate := c.Param("coordinate")
-	artifactVersion := c.Param("version")
+	artifactVersion, svg := badgeVersion(c.Param("version"))
 	theme := c.QueryParam("theme")
 
 	registry, err := url.QueryUnescape(registry)
@@ -34,6 +35,9 @@ func (h handlers) projectBadgeHandler(c echo.Context) error {
 	if registry == "" {
 		registry = "repo.maven.apache.org/maven2" // default to Maven Central
 	}
+	if err = validateRegistry(registry); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
 	if coordinate == "" {
 		return c.JSON(http.StatusBadRequest, "param coordinate is required")
 	}
@@ -49,27 +53,29 @@ func (h handlers) projectBadgeHandler(c echo.Context) error {
 	data, err := h.lookupService.LookupProject(registry, gav)
 	if err != nil {
 		if errors.Is(err, service.ErrRegistryNotFound) {
-			return c.JSON(http.StatusOK, badge.NewDependencyBadge("repository not configured", badge.Error, theme))
+			return renderBadge(c, svg, badge.NewDependencyBadge("repository not configured", badge.Error, theme))
 		} else if errors.Is(err, service.ErrDependencyNotFound) {
-			return c.JSON(http.StatusOK, badge.NewDependencyBadge("not configured", badge.Error, theme))
+			return renderBadge(c, svg, badge.NewDependencyBadge("not configured", badge.Error, theme))
 		}
 
 		slog.Error("Error looking up dependency metadata", "err", err)
 		return c.JSON(http.StatusInternalServerError, "internal server error")
 	}
 
-	// support "latest" as version
-	if artifactVersion == "latest" {
-		artifactVersion = data.Latest
+	// support version aliases (latest, latest-verified, latest-reproducible)
+	alias := artifactVersion
+	artifactVersion = data.ResolveVersion(artifactVersion)
+	if artifactVersion == "" {
+		return renderBadge(c, svg, badge.NewDependencyBadge(aliasNotFoundMessage(alias), badge.Warning, theme))
 	}
 
 	// search version in data
 	version, ok := data.Versions[artifactVersion]
 	if !ok {
-		return c.JSON(http.StatusOK, badge.NewDependencyBadge("pending verification", badge.Warning, theme))
+		return renderBadge(c, svg, badge.NewDependencyBadge("pending verification", badge.Warning, theme))
 	}
 
-	return c.JSON(http.StatusOK, badge.NewDependencyBadge(
+	return renderBadge(c, svg, badge.NewDependencyBadge(
 		fmt.Sprintf("%s - %d/%d ok", artifactVersion, version.FileStats.TotalReproducibleFiles, version.FileStats.TotalReproducibleFiles+version.FileStats.TotalNonReproducibleFiles),
 		util.Ternary(version.Reproducible, badge.Success, badge.Error),
 		theme),
@@ -79,7 +85,7 @@ func (h handlers) projectBadgeHandler(c echo.Context) error {
 func (h handlers) dependencyBadgeHandler(c echo.Context) error {
 	registry := c.Param("registry")
 	coordinate := c.Param("coordinate")
-	artifactVersion := c.Param("version")
+	artifactVersion, svg := badgeVersion(c.Param("version"))
 	theme := c.QueryParam("theme")
 	scope := c.QueryParam("scope") // project or module
 
@@ -95,6 +101,9 @@ func (h handlers) dependencyBadgeHandler(c echo.Context) error {
 	if registry == "" {
 		registry = "repo.maven.apache.org/maven2" // default to Maven Central
 	}
+	if err = validateRegistry(registry); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
 	if coordinate == "" {
 		return c.JSON(http.StatusBadRequest, "param coordinate is required")
 	}
@@ -113,24 +122,26 @@ func (h handlers) dependencyBadgeHandler(c echo.Context) error {
 	data, err := h.lookupService.LookupDependency(registry, gav)
 	if err != nil {
 		if errors.Is(err, service.ErrRegistryNotFound) {
-			return c.JSON(http.StatusOK, badge.NewDependencyBadge("repository not configured", badge.Error, theme))
+			return renderBadge(c, svg, badge.NewDependencyBadge("repository not configured", badge.Error, theme))
 		} else if errors.Is(err, service.ErrDependencyNotFound) {
-			return c.JSON(http.StatusOK, badge.NewDependencyBadge("not configured", badge.Error, theme))
+			return renderBadge(c, svg, badge.NewDependencyBadge("not configured", badge.Error, theme))
 		}
 
 		slog.Error("Error looking up dependency metadata", "err", err)
 		return c.JSON(http.StatusInternalServerError, "internal server error")
 	}
 
-	// support "latest" as version
-	if artifactVersion == "latest" {
-		artifactVersion = data.Latest
+	// support version aliases (latest, latest-verified, latest-reproducible)
+	alias := artifactVersion
+	artifactVersion = data.ResolveVersion(artifactVersion)
+	if artifactVersion == "" {
+		return renderBadge(c, svg, badge.NewDependencyBadge(aliasNotFoundMessage(alias), badge.Warning, theme))
 	}
 
 	// search version in data
 	version, ok := data.Versions[artifactVersion]
 	if !ok {
-		return c.JSON(http.StatusOK, badge.NewDependencyBadge("pending verification", badge.Warning, theme))
+		return renderBadge(c, svg, badge.NewDependencyBadge("pending verification", badge.Warning, theme))
 	}
 
 	// badge
@@ -141,17 +152,20 @@ func (h handlers) dependencyBadgeHandler(c echo.Context) error {
 		badgeStatus = util.Ternary(version.FileStats.ModuleNonReproducibleFiles == 0, badge.Success, badge.Error)
 	}
 
-	return c.JSON(http.StatusOK, badge.NewDependencyBadge(
+	return renderBadge(c, svg, badge.NewDependencyBadge(
 		badgeText,
 		badgeStatus,
 		theme),
 	)
 }
 
+// ResolveTimeout bounds the dependency resolution of a single transitive dependency badge request
+const ResolveTimeout = 20 * time.Second
+
 func (h handlers) transitiveDependencyBadgeHandler(c echo.Context) error {
 	registry := c.Param("registry")
 	coordinate := c.Param("coordinate")
-	artifactVersion := c.Param("version")
+	artifactVersion, svg := badgeVersion(c.Param("version"))
 	theme := c.QueryParam("theme")
 
 	registry, err := url.QueryUnescape(registry)
@@ -166,6 +180,9 @@ func (h handlers) transitiveDependencyBadgeHandler(c echo.Context) error {
 	if registry == "" {
 		registry = "repo.maven.apache.org/maven2" // default to Maven Central
 	}
+	if err = validateRegistry(registry); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
 	if coordinate == "" {
 		return c.JSON(http.StatusBadRequest, "param coordinate is required")
 	}
@@ -177,48 +194,90 @@ func (h handlers) transitiveDependencyBadgeHandler(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, "invalid maven coordinate")
 	}
 
-	// collect coordinates (includes special handling for BOMs)
-	coordinates, err := h.lookupService.CollectCoordinates(registry, gav)
-	if err != nil {
-		slog.Error("Error collecting coordinates", "err", err)
-		return c.JSON(http.StatusInternalServerError, "internal server error")
-	}
+	// support version aliases (latest, latest-verified, latest-reproducible)
+	if model.IsVersionAlias(gav.Version) {
+		data, lErr := h.lookupService.LookupDependency(registry, gav)
+		if lErr != nil {
+			if errors.Is(lErr, service.ErrRegistryNotFound) {
+				return renderBadge(c, svg, badge.NewDependencyBadge("repository not configured", badge.Error, theme))
+			} else if errors.Is(lErr, service.ErrDependencyNotFound) {
+				return renderBadge(c, svg, badge.NewDependencyBadge("not configured", badge.Error, theme))
+			}
 
-	// lookup transitive dependencies
-	var dependencies []sonatype.Component
-	for _, cord := range coordinates {
-		dep, dErr := sonatype.FetchAllDependencies(fmt.Sprintf("pkg:maven/%s/%s@%s", cord.GroupId, cord.ArtifactId, cord.Version))
-		if dErr != nil {
-			slog.Error("Error fetching transitive dependencies", "err", err)
+			slog.Error("Error looking up dependency metadata", "err", lErr)
 			return c.JSON(http.StatusInternalServerError, "internal server error")
 		}
 
-		dependencies = append(dependencies, dep...)
+		gav.Version = data.ResolveVersion(gav.Version)
+		if gav.Version == "" {
+			return renderBadge(c, svg, badge.NewDependencyBadge(aliasNotFoundMessage(artifactVersion), badge.Warning, theme))
+		}
+	}
+
+	// resolve transitive dependencies based on the poms (includes special handling for BOMs)
+	ctx, cancel := context.WithTimeout(c.Request().Context(), ResolveTimeout)
+	defer cancel()
+	dependencies, err := service.ResolveTransitiveDependencies(ctx, h.lookupService, registry, gav)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return renderBadge(c, svg, badge.NewDependencyBadge("resolution timed out", badge.Warning, theme))
+		} else if errors.Is(err, service.ErrDependencyNotFound) {
+			return renderBadge(c, svg, badge.NewDependencyBadge("pom not found", badge.Error, theme))
+		}
+
+		slog.Error("Error resolving transitive dependencies", "err", err)
+		return c.JSON(http.StatusInternalServerError, "internal server error")
 	}
-	dependencies = slices.CompactFunc(dependencies, sonatype.ComponentEquals)
 
 	// evaluate dependencies
 	var allDependencies []string
 	var reproducibleDependencies []string
 	for _, dep := range dependencies {
-		allDependencies = append(allDependencies, fmt.Sprintf("%s:%s:%s", dep.DependencyNamespace, dep.DependencyName, dep.DependencyVersion))
+		allDependencies = append(allDependencies, dep.Coordinate())
 
-		dResult, dErr := h.lookupService.LookupDependencyVersion(registry, model.GAV{GroupId: dep.DependencyNamespace, ArtifactId: dep.DependencyName, Version: dep.DependencyVersion})
+		dResult, dErr := h.lookupService.LookupDependencyVersion(registry, dep)
 		if dErr != nil {
 			continue
 		}
 
 		if dResult.FileStats.TotalNonReproducibleFiles == 0 {
-			reproducibleDependencies = append(reproducibleDependencies, fmt.Sprintf("%s:%s:%s", dep.DependencyNamespace, dep.DependencyName, dep.DependencyVersion))
+			reproducibleDependencies = append(reproducibleDependencies, dep.Coordinate())
 		}
 	}
 
 	// badge
 	badgeText := fmt.Sprintf("%d/%d dep(s)", len(reproducibleDependencies), len(allDependencies))
 	badgeStatus := util.Ternary(len(reproducibleDependencies) == len(allDependencies), badge.Success, badge.Warning)
-	return c.JSON(http.StatusOK, badge.NewDependencyBadge(
+	return renderBadge(c, svg, badge.NewDependencyBadge(
 		badgeText,
 		badgeStatus,
 		theme),
 	)
 }
+
+// badgeVersion strips the .svg suffix from the version param, which is used to request a svg image instead of the shields.io endpoint json
+func badgeVersion(version string) (string, bool) {
+	if strings.HasSuffix(version, ".svg") {
+		return strings.TrimSuffix(version, ".svg"), true
+	}
+	return version, false
+}
+
+// renderBadge writes the badge as shields.io endpoint json or as svg image (supports the styles flat, flat-square and for-the-badge)
+func renderBadge(c echo.Context, svg bool, b *badge.Badge) error {
+	if svg {
+		return c.Blob(http.StatusOK, "image/svg+xml", badge.RenderSVG(b, badge.ParseStyle(c.QueryParam("style"))))
+	}
+	return c.JSON(http.StatusOK, b)
+}
+
+// aliasNotFoundMessage returns the badge message for version aliases that can not be resolved
+func aliasNotFoundMessage(alias string) string {
+	switch alias {
+	case model.VersionLatestReproducible:
+		return "no reproducible version"
+	case model.VersionLatestVerified:
+		return "no verified version"
+	}
+	return "pending verification"
+}
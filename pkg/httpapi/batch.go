@@ -0,0 +1,46 @@
+package httpapi
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/model"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/service"
+)
+
+// MaxBatchSize is the maximum number of coordinates per batch request
+const MaxBatchSize = 1000
+
+type batchLookupRequest struct {
+	Registry    string   `json:"registry"`
+	Coordinates []string `json:"coordinates"`
+}
+
+type batchLookupResponse struct {
+	Results []model.LookupResult `json:"results"`
+}
+
+func (h handlers) batchLookupHandler(c echo.Context) error {
+	var req batchLookupRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, "invalid request body")
+	}
+
+	if req.Registry == "" {
+		req.Registry = "repo.maven.apache.org/maven2" // default to Maven Central
+	}
+	if err := validateRegistry(req.Registry); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	if len(req.Coordinates) == 0 {
+		return c.JSON(http.StatusBadRequest, "coordinates are required")
+	}
+	if len(req.Coordinates) > MaxBatchSize {
+		return c.JSON(http.StatusBadRequest, fmt.Sprintf("too many coordinates, at most %d are allowed per request", MaxBatchSize))
+	}
+
+	return c.JSON(http.StatusOK, batchLookupResponse{
+		Results: service.LookupStatuses(h.lookupService, req.Registry, req.Coordinates, h.batchConcurrency),
+	})
+}
@@ -0,0 +1,89 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/feed"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/model"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/service"
+)
+
+func (h handlers) feedHandler(c echo.Context) error {
+	query, err := feedParams(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	registry := c.QueryParam("registry")
+	if registry == "" {
+		registry = "repo.maven.apache.org/maven2" // default to Maven Central
+	}
+	if err = validateRegistry(registry); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	index, err := h.lookupService.LookupFeedIndex(registry)
+	if err != nil {
+		return queryError(c, err)
+	}
+
+	meta := feed.Meta{ID: "all", Title: "Reproducible Builds: all projects", Link: c.Scheme() + "://" + c.Request().Host + c.Request().RequestURI, Updated: time.Now()}
+	switch {
+	case query.Project != "":
+		meta.ID, meta.Title = "project:"+query.Project, "Reproducible Builds: "+query.Project
+	case query.GroupID != "":
+		meta.ID, meta.Title = "group:"+query.GroupID, "Reproducible Builds: "+query.GroupID
+	}
+	if c.QueryString() != "" {
+		meta.ID += "?" + c.QueryString()
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, feed.ContentType+"; charset=utf-8")
+	c.Response().WriteHeader(http.StatusOK)
+	return feed.Write(c.Response(), feed.New(meta, service.FilterFeed(index, query)))
+}
+
+// feedParams parses the query parameters of the feed endpoint
+func feedParams(c echo.Context) (service.FeedQuery, error) {
+	query := service.FeedQuery{
+		GroupID: c.QueryParam("group"),
+		Project: c.QueryParam("project"),
+		Status:  model.ReproducibilityStatus(c.QueryParam("status")),
+		Event:   model.HistoryEvent(c.QueryParam("event")),
+	}
+
+	if query.Project != "" {
+		gav, err := model.NewGAV(query.Project)
+		if err != nil || gav.Version != "" {
+			return query, errors.New("param project must be groupId:artifactId")
+		}
+		query.Project = gav.GroupId + ":" + gav.ArtifactId
+	}
+	if query.Status != "" && !slices.Contains([]model.ReproducibilityStatus{model.StatusReproducible, model.StatusPartiallyReproducible, model.StatusNotReproducible}, query.Status) {
+		return query, errors.New("param status must be reproducible, partially-reproducible or not-reproducible")
+	}
+	if query.Event != "" && query.Event != model.HistoryEventFirstSeen && query.Event != model.HistoryEventStatusChanged {
+		return query, errors.New("param event must be first-seen or status-changed")
+	}
+	if value := c.QueryParam("regression"); value != "" {
+		regression, err := strconv.ParseBool(value)
+		if err != nil {
+			return query, errors.New("param regression must be true or false")
+		}
+		query.Regression = regression
+	}
+	if value := c.QueryParam("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > service.MaxFeedLimit {
+			return query, errors.New("param limit must be between 1 and " + strconv.Itoa(service.MaxFeedLimit))
+		}
+		query.Limit = limit
+	}
+
+	return query, nil
+}
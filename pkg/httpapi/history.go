@@ -0,0 +1,46 @@
+package httpapi
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/labstack/echo/v4"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/model"
+)
+
+func (h handlers) historyHandler(c echo.Context) error {
+	registry, err := url.QueryUnescape(c.Param("registry"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "failed to decode registry")
+	}
+	coordinate, err := url.QueryUnescape(c.Param("coordinate"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "failed to decode coordinate")
+	}
+	if registry == "" {
+		registry = "repo.maven.apache.org/maven2" // default to Maven Central
+	}
+	if err = validateRegistry(registry); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+	gav, err := model.NewGAV(coordinate)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, "invalid maven coordinate")
+	}
+
+	history, err := h.lookupService.LookupProjectHistory(registry, gav)
+	if err != nil {
+		return queryError(c, err)
+	}
+
+	// optional filter to a single version
+	if version := c.QueryParam("version"); version != "" {
+		vh, ok := history.Versions[version]
+		if !ok {
+			return c.JSON(http.StatusNotFound, "no history for version")
+		}
+		history.Versions = map[string]*model.VersionHistory{version: vh}
+	}
+
+	return c.JSON(http.StatusOK, history)
+}
@@ -0,0 +1,95 @@
+package httpapi
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/model"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/service"
+)
+
+func (h handlers) projectQueryHandler(c echo.Context) error {
+	registry, gav, err := coordinateParams(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	data, err := h.lookupService.LookupProjectVersionDetails(registry, gav)
+	if err != nil {
+		return queryError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, data)
+}
+
+func (h handlers) dependencyQueryHandler(c echo.Context) error {
+	registry, gav, err := coordinateParams(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	data, err := h.lookupService.LookupDependencyVersionDetails(registry, gav)
+	if err != nil {
+		return queryError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, data)
+}
+
+// coordinateParams parses the registry, coordinate and version path params
+func coordinateParams(c echo.Context) (string, model.GAV, error) {
+	registry, err := url.QueryUnescape(c.Param("registry"))
+	if err != nil {
+		return "", model.GAV{}, errors.New("failed to decode registry")
+	}
+	coordinate, err := url.QueryUnescape(c.Param("coordinate"))
+	if err != nil {
+		return "", model.GAV{}, errors.New("failed to decode coordinate")
+	}
+	artifactVersion := c.Param("version")
+
+	if registry == "" {
+		registry = "repo.maven.apache.org/maven2" // default to Maven Central
+	}
+	if err = validateRegistry(registry); err != nil {
+		return "", model.GAV{}, err
+	}
+	if coordinate == "" {
+		return "", model.GAV{}, errors.New("param coordinate is required")
+	}
+	if artifactVersion == "" {
+		return "", model.GAV{}, errors.New("param version is required")
+	}
+	gav, err := model.NewGAV(coordinate + ":" + artifactVersion)
+	if err != nil {
+		return "", model.GAV{}, errors.New("invalid maven coordinate")
+	}
+
+	return registry, gav, nil
+}
+
+// validateRegistry rejects registries that would be read from the local filesystem of the server
+func validateRegistry(registry string) error {
+	if strings.HasPrefix(strings.ToLower(registry), "file:") {
+		return errors.New("param registry must be a remote repository")
+	}
+	return nil
+}
+
+// queryError maps lookup errors to http responses
+func queryError(c echo.Context, err error) error {
+	if errors.Is(err, service.ErrRegistryNotFound) {
+		return c.JSON(http.StatusBadRequest, "repository not configured")
+	} else if errors.Is(err, service.ErrDependencyNotFound) {
+		return c.JSON(http.StatusNotFound, "not configured")
+	} else if errors.Is(err, service.ErrVersionNotFound) {
+		return c.JSON(http.StatusNotFound, "pending verification")
+	}
+
+	slog.Error("Error looking up dependency metadata", "err", err)
+	return c.JSON(http.StatusInternalServerError, "internal server error")
+}
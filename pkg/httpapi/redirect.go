@@ -13,9 +13,13 @@ import (
 func (h handlers) redirectHandler(c echo.Context) error {
 	registry := c.Param("registry")
 	coordinate := c.Param("coordinate")
+	artifactVersion := c.Param("version")
 	if registry == "" {
 		registry = "repo.maven.apache.org/maven2" // default to Maven Central
 	}
+	if err := validateRegistry(registry); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
 	if coordinate == "" {
 		return c.JSON(http.StatusBadRequest, "query param coordinate is required")
 	}
@@ -37,6 +41,11 @@ func (h handlers) redirectHandler(c echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, "internal server error")
 	}
 
+	// version aliases (latest, latest-verified, latest-reproducible) redirect to the documentation if no matching version exists
+	if model.IsVersionAlias(artifactVersion) && data.ResolveVersion(artifactVersion) == "" {
+		return c.Redirect(http.StatusFound, "https://reproducible-builds.org/docs/jvm/")
+	}
+
 	// redirect
 	return c.Redirect(http.StatusFound, data.RebuildProjectUrl)
 }
@@ -0,0 +1,74 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/model"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/service"
+)
+
+func (h handlers) searchHandler(c echo.Context) error {
+	query, err := searchParams(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	registry := c.QueryParam("registry")
+	if registry == "" {
+		registry = "repo.maven.apache.org/maven2" // default to Maven Central
+	}
+	if err = validateRegistry(registry); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	index, err := h.lookupService.LookupSearchIndex(registry)
+	if err != nil {
+		return queryError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, service.Search(index, query))
+}
+
+// searchParams parses the query parameters of the search endpoint
+func searchParams(c echo.Context) (service.SearchQuery, error) {
+	query := service.SearchQuery{
+		Query:     c.QueryParam("q"),
+		Match:     c.QueryParam("match"),
+		Type:      c.QueryParam("type"),
+		BuildTool: c.QueryParam("buildTool"),
+	}
+
+	if query.Match != "" && query.Match != service.SearchMatchPrefix && query.Match != service.SearchMatchSubstring {
+		return query, errors.New("param match must be prefix or substring")
+	}
+	if query.Type != "" && !slices.Contains([]string{model.SearchTypeProject, model.SearchTypeMaven}, query.Type) {
+		return query, errors.New("param type must be project or maven")
+	}
+	if value := c.QueryParam("reproducible"); value != "" {
+		reproducible, err := strconv.ParseBool(value)
+		if err != nil {
+			return query, errors.New("param reproducible must be true or false")
+		}
+		query.Reproducible = &reproducible
+	}
+	if value := c.QueryParam("offset"); value != "" {
+		offset, err := strconv.Atoi(value)
+		if err != nil || offset < 0 {
+			return query, errors.New("param offset must be a positive number")
+		}
+		query.Offset = offset
+	}
+	if value := c.QueryParam("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > service.MaxSearchLimit {
+			return query, errors.New("param limit must be between 1 and " + strconv.Itoa(service.MaxSearchLimit))
+		}
+		query.Limit = limit
+	}
+
+	return query, nil
+}
@@ -0,0 +1,63 @@
+package httpapi
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/badge"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/service"
+)
+
+func (h handlers) statsHandler(c echo.Context) error {
+	registry := c.QueryParam("registry")
+	if registry == "" {
+		registry = "repo.maven.apache.org/maven2" // default to Maven Central
+	}
+	if err := validateRegistry(registry); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	stats, err := h.lookupService.LookupStats(registry)
+	if err != nil {
+		return queryError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, stats)
+}
+
+// statsBadgeHandler renders the percentage of reproducible versions of the registry
+func (h handlers) statsBadgeHandler(c echo.Context) error {
+	svg := strings.HasSuffix(c.Path(), ".svg")
+	theme := c.QueryParam("theme")
+	registry := c.QueryParam("registry")
+	if registry == "" {
+		registry = "repo.maven.apache.org/maven2" // default to Maven Central
+	}
+	if err := validateRegistry(registry); err != nil {
+		return c.JSON(http.StatusBadRequest, err.Error())
+	}
+
+	stats, err := h.lookupService.LookupStats(registry)
+	if err != nil {
+		if errors.Is(err, service.ErrRegistryNotFound) || errors.Is(err, service.ErrDependencyNotFound) {
+			return renderBadge(c, svg, badge.NewDependencyBadge("repository not configured", badge.Error, theme))
+		}
+
+		slog.Error("Error looking up stats", "err", err)
+		return c.JSON(http.StatusInternalServerError, "internal server error")
+	}
+
+	rate := stats.Overall.VersionRate
+	badgeStatus := badge.Error
+	switch {
+	case rate >= 80:
+		badgeStatus = badge.Success
+	case rate >= 50:
+		badgeStatus = badge.Warning
+	}
+	return renderBadge(c, svg, badge.NewDependencyBadge(fmt.Sprintf("%.1f%% of %d versions", rate, stats.Versions), badgeStatus, theme))
+}
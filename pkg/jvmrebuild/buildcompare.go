@@ -0,0 +1,138 @@
+package jvmrebuild
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/util"
+)
+
+// BuildCompare contains the result of comparing the rebuilt artifacts against the reference artifacts
+type BuildCompare struct {
+	Version string
+	// Comparison result
+	OK      int
+	KO      int
+	OKFiles []string
+	KOFiles []string
+	// Reference and rebuild environment
+	ReferenceJavaVersion string
+	ReferenceOSName      string
+	ReferenceRepository  string
+	RebuildRepository    string
+	// Diffoscope commands and links to inspect the differences
+	Diffoscope []Diffoscope
+}
+
+// Diffoscope describes how to inspect the differences of a non-reproducible file
+type Diffoscope struct {
+	File      string // name of the non-reproducible file
+	Command   string // diffoscope command to compare the reference and rebuilt file
+	Reference string // path of the reference file
+	Rebuild   string // path of the rebuilt file
+	Link      string // link to the published diffoscope output
+}
+
+// Reproducible returns true if at least one file was compared and all compared files are identical
+func (c BuildCompare) Reproducible() bool {
+	return c.KO == 0 && c.OK > 0
+}
+
+// Status returns the comparison status of a file: ok, ko or an empty string if the file was not compared
+func (c BuildCompare) Status(file string) string {
+	if slices.Contains(c.OKFiles, file) {
+		return "ok"
+	}
+	if slices.Contains(c.KOFiles, file) {
+		return "ko"
+	}
+	return ""
+}
+
+// DiffoscopeFor returns the diffoscope information for the given file
+func (c BuildCompare) DiffoscopeFor(file string) (Diffoscope, bool) {
+	for _, d := range c.Diffoscope {
+		if d.File == file {
+			return d, true
+		}
+	}
+	return Diffoscope{}, false
+}
+
+func ParseBuildCompare(file string) (BuildCompare, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return BuildCompare{}, err
+	}
+
+	return ParseBuildCompareContent(string(content)), nil
+}
+
+func ParseBuildCompareContent(content string) BuildCompare {
+	kv := util.ParseProperties(content)
+
+	buildCompare := BuildCompare{
+		Version:              kv["version"],
+		OKFiles:              strings.Fields(kv["okFiles"]),
+		KOFiles:              strings.Fields(kv["koFiles"]),
+		ReferenceJavaVersion: kv["reference_java_version"],
+		ReferenceOSName:      kv["reference_os_name"],
+		ReferenceRepository:  kv["reference_repository"],
+		RebuildRepository:    kv["rebuild_repository"],
+	}
+	buildCompare.OK, _ = strconv.Atoi(kv["ok"])
+	buildCompare.KO, _ = strconv.Atoi(kv["ko"])
+
+	// diffoscope commands and links are written as comments, e.g. "# diffoscope target/reference/<groupId>/<file> <module>/target/<file>"
+	var links []string
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "#"))
+		if strings.HasPrefix(line, "diffoscope ") {
+			args := strings.Fields(line)
+			d := Diffoscope{Command: line}
+			if len(args) >= 3 {
+				d.Reference = args[len(args)-2]
+				d.Rebuild = args[len(args)-1]
+				d.File = filepath.Base(d.Rebuild)
+			}
+			buildCompare.Diffoscope = append(buildCompare.Diffoscope, d)
+		} else if (strings.HasPrefix(line, "https://") || strings.HasPrefix(line, "http://")) && strings.Contains(line, "diffoscope") {
+			links = append(links, strings.Fields(line)[0])
+		}
+	}
+
+	// assign links to the file they are referring to
+	for _, link := range links {
+		file := matchFileName(link, buildCompare.KOFiles)
+		if file == "" {
+			continue
+		}
+
+		assigned := false
+		for i := range buildCompare.Diffoscope {
+			if buildCompare.Diffoscope[i].File == file {
+				buildCompare.Diffoscope[i].Link = link
+				assigned = true
+			}
+		}
+		if !assigned {
+			buildCompare.Diffoscope = append(buildCompare.Diffoscope, Diffoscope{File: file, Link: link})
+		}
+	}
+
+	return buildCompare
+}
+
+// matchFileName returns the longest file name that is contained in the given link
+func matchFileName(link string, files []string) string {
+	result := ""
+	for _, f := range files {
+		if strings.Contains(link, f) && len(f) > len(result) {
+			result = f
+		}
+	}
+	return result
+}
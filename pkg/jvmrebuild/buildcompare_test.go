@@ -0,0 +1,65 @@
+package jvmrebuild
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseBuildCompare(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		want     BuildCompare
+		wantErr  bool
+	}{
+		{
+			name:     "Maven - Not Reproducible",
+			filename: "testdata/maven-ko.buildcompare",
+			want: BuildCompare{
+				Version:              "1.1",
+				OK:                   2,
+				KO:                   1,
+				OKFiles:              []string{"foo-1.1.pom", "foo-core-1.1.jar"},
+				KOFiles:              []string{"foo-1.1.jar"},
+				ReferenceJavaVersion: "17 (from MANIFEST.MF Build-Jdk-Spec)",
+				ReferenceOSName:      "Unix",
+				ReferenceRepository:  "https://repo.maven.apache.org/maven2",
+				RebuildRepository:    "target/reference",
+				Diffoscope: []Diffoscope{
+					{
+						File:      "foo-1.1.jar",
+						Command:   "diffoscope target/reference/com.example/foo-1.1.jar foo/target/foo-1.1.jar",
+						Reference: "target/reference/com.example/foo-1.1.jar",
+						Rebuild:   "foo/target/foo-1.1.jar",
+						Link:      "https://github.com/jvm-repo-rebuild/reproducible-central/blob/master/content/com/example/foo/foo-1.1.jar.diffoscope",
+					},
+				},
+			},
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := filepath.Abs(tt.filename)
+			if err != nil {
+				t.Fatalf("Failed to get absolute path: %v", err)
+			}
+
+			got, err := ParseBuildCompare(path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseBuildCompare() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ParseBuildCompare() mismatch (-want +got):\n%s", diff)
+			}
+			if got.Reproducible() {
+				t.Errorf("Reproducible() = true, want false")
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package jvmrebuild
+
+import (
+	"regexp"
+
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/util"
+)
+
+// BuildSpec contains the instructions used by reproducible-central to rebuild a release, see https://github.com/jvm-repo-rebuild/reproducible-central/blob/master/doc/BUILDSPEC.md
+type BuildSpec struct {
+	GroupID    string
+	ArtifactID string
+	Display    string
+	Version    string
+	// Source information
+	GitRepo string
+	GitTag  string
+	// Build environment
+	Tool    string
+	JDK     string
+	Newline string
+	Umask   string
+	// Build instructions
+	Command   string
+	BuildInfo string
+}
+
+var buildSpecVariable = regexp.MustCompile(`\$\{([^}]+)}`)
+
+func ParseBuildSpec(file string) (BuildSpec, error) {
+	kv, err := util.ParseFile(file)
+	if err != nil {
+		return BuildSpec{}, err
+	}
+
+	// buildspec files are shell scripts, resolve references to other variables (e.g. gitTag=v${version})
+	get := func(key string) string {
+		return interpolateBuildSpec(kv, kv[key], 0)
+	}
+
+	return BuildSpec{
+		GroupID:    get("groupId"),
+		ArtifactID: get("artifactId"),
+		Display:    get("display"),
+		Version:    get("version"),
+		GitRepo:    get("gitRepo"),
+		GitTag:     get("gitTag"),
+		Tool:       get("tool"),
+		JDK:        get("jdk"),
+		Newline:    get("newline"),
+		Umask:      get("umask"),
+		Command:    get("command"),
+		BuildInfo:  get("buildinfo"),
+	}, nil
+}
+
+func interpolateBuildSpec(kv map[string]string, value string, depth int) string {
+	if depth > 10 {
+		return value // stop on cyclic references
+	}
+
+	return buildSpecVariable.ReplaceAllStringFunc(value, func(match string) string {
+		ref, ok := kv[buildSpecVariable.FindStringSubmatch(match)[1]]
+		if !ok {
+			return match
+		}
+		return interpolateBuildSpec(kv, ref, depth+1)
+	})
+}
@@ -0,0 +1,62 @@
+package jvmrebuild
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseBuildSpec(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		want     BuildSpec
+		wantErr  bool
+	}{
+		{
+			name:     "Maven",
+			filename: "testdata/maven.buildspec",
+			want: BuildSpec{
+				GroupID:    "com.fasterxml.jackson.core",
+				ArtifactID: "jackson-databind",
+				Display:    "com.fasterxml.jackson.core:jackson-databind",
+				Version:    "2.18.0",
+				GitRepo:    "https://github.com/FasterXML/jackson-databind.git",
+				GitTag:     "jackson-databind-2.18.0",
+				Tool:       "mvn-3.9.9",
+				JDK:        "8",
+				Newline:    "lf",
+				Umask:      "022",
+				Command:    "mvn -Prelease clean package -DskipTests -Dmaven.javadoc.skip -Dgpg.skip",
+				BuildInfo:  "target/jackson-databind-2.18.0.buildinfo",
+			},
+			wantErr: false,
+		},
+		{
+			name:     "Missing File",
+			filename: "testdata/missing.buildspec",
+			want:     BuildSpec{},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := filepath.Abs(tt.filename)
+			if err != nil {
+				t.Fatalf("Failed to get absolute path: %v", err)
+			}
+
+			got, err := ParseBuildSpec(path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseBuildSpec() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ParseBuildSpec() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Catalog lists the shards of the compact catalog, each shard contains all artifacts of a group prefix
+type Catalog struct {
+	Shards []CatalogShard `json:"shards"`
+}
+
+type CatalogShard struct {
+	Prefix  string `json:"prefix"`  // first segment of the groupId, e.g. "io"
+	File    string `json:"file"`    // gzip compressed file, relative to the catalog index
+	Entries int    `json:"entries"` // number of artifacts
+	SHA256  string `json:"sha256"`  // checksum of the compressed file
+}
+
+// CatalogEntry is a single artifact of the catalog, the version is the latest rebuilt version
+type CatalogEntry struct {
+	GroupID      string
+	ArtifactID   string
+	Version      string
+	Reproducible bool
+	Project      string // project key (groupId:artifactId)
+}
+
+// String returns the tab separated catalog line: groupId, artifactId, version, reproducible flag and project key
+func (e CatalogEntry) String() string {
+	return strings.Join([]string{e.GroupID, e.ArtifactID, e.Version, strconv.FormatBool(e.Reproducible), e.Project}, "\t")
+}
+
+// ParseCatalogEntry parses a single catalog line
+func ParseCatalogEntry(line string) (CatalogEntry, error) {
+	parts := strings.Split(line, "\t")
+	if len(parts) != 5 {
+		return CatalogEntry{}, fmt.Errorf("invalid catalog line, expected 5 fields but got %d", len(parts))
+	}
+	reproducible, err := strconv.ParseBool(parts[3])
+	if err != nil {
+		return CatalogEntry{}, fmt.Errorf("invalid reproducible flag: %s", parts[3])
+	}
+
+	return CatalogEntry{
+		GroupID:      parts[0],
+		ArtifactID:   parts[1],
+		Version:      parts[2],
+		Reproducible: reproducible,
+		Project:      parts[4],
+	}, nil
+}
+
+// CatalogPrefix returns the shard prefix of a groupId
+func CatalogPrefix(groupId string) string {
+	prefix, _, _ := strings.Cut(groupId, ".")
+	return prefix
+}
@@ -0,0 +1,73 @@
+package model
+
+import (
+	"maps"
+	"slices"
+	"time"
+)
+
+// FeedIndex lists the rebuild results of a registry, ordered from newest to oldest
+type FeedIndex struct {
+	Entries []FeedEntry `json:"entries"`
+}
+
+// FeedEntry is a newly rebuilt version or a status change of a version
+type FeedEntry struct {
+	GroupID           string                `json:"group_id"`
+	ArtifactID        string                `json:"artifact_id"`
+	Version           string                `json:"version"`
+	Date              time.Time             `json:"date"`
+	Event             HistoryEvent          `json:"event"` // first-seen or status-changed
+	Status            ReproducibilityStatus `json:"status"`
+	PreviousStatus    ReproducibilityStatus `json:"previous_status,omitempty"`
+	Regression        bool                  `json:"regression,omitempty"` // the status is worse than the previous status
+	FileStats         FileStats             `json:"file_stats"`
+	RebuildProjectUrl string                `json:"rebuild_project_url,omitempty"`
+}
+
+// Coordinate returns groupId:artifactId
+func (e FeedEntry) Coordinate() string {
+	return e.GroupID + ":" + e.ArtifactID
+}
+
+// NewFeedEntries returns the first-seen and status-changed entries of a project history, other events are not relevant for subscribers
+func NewFeedEntries(history *History, rebuildProjectUrl string) []FeedEntry {
+	var entries []FeedEntry
+	for _, version := range slices.Sorted(maps.Keys(history.Versions)) {
+		for _, entry := range history.Versions[version].Entries {
+			if entry.Event != HistoryEventFirstSeen && entry.Event != HistoryEventStatusChanged {
+				continue
+			}
+
+			feedEntry := FeedEntry{
+				GroupID:           history.GroupID,
+				ArtifactID:        history.ArtifactID,
+				Version:           version,
+				Date:              entry.Date,
+				Event:             entry.Event,
+				Status:            entry.Status,
+				PreviousStatus:    entry.PreviousStatus,
+				Regression:        entry.Event == HistoryEventStatusChanged && statusRank(entry.Status) < statusRank(entry.PreviousStatus),
+				RebuildProjectUrl: rebuildProjectUrl,
+			}
+			if entry.FileStats != nil {
+				feedEntry.FileStats = *entry.FileStats
+			}
+			entries = append(entries, feedEntry)
+		}
+	}
+	return entries
+}
+
+// statusRank orders the reproducibility status of rebuilt versions, higher is better
+func statusRank(status ReproducibilityStatus) int {
+	switch status {
+	case StatusReproducible:
+		return 3
+	case StatusPartiallyReproducible:
+		return 2
+	case StatusNotReproducible:
+		return 1
+	}
+	return 0
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"errors"
+	"net/url"
 	"strings"
 	"unicode"
 )
@@ -33,6 +34,56 @@ func NewGAV(coordinate string) (GAV, error) {
 	return parseMavenCoordinate(coordinate)
 }
 
+// NewGAVFromPurl creates a new GAV struct from a maven package url, e.g. pkg:maven/io.github.xanthic.cache/cache-api@0.6.2
+func NewGAVFromPurl(purl string) (GAV, error) {
+	if !strings.HasPrefix(purl, "pkg:maven/") {
+		return GAV{}, errors.New("invalid purl: expected type maven")
+	}
+
+	// strip qualifiers and subpath, they are not relevant to identify the artifact
+	remainder := strings.TrimPrefix(purl, "pkg:maven/")
+	if i := strings.IndexAny(remainder, "?#"); i != -1 {
+		remainder = remainder[:i]
+	}
+
+	nameAndNamespace, version, _ := strings.Cut(remainder, "@")
+	namespace, name, found := strings.Cut(nameAndNamespace, "/")
+	if !found || namespace == "" || name == "" {
+		return GAV{}, errors.New("invalid purl: expected format is 'pkg:maven/groupId/artifactId@version'")
+	}
+
+	var err error
+	gav := GAV{}
+	if gav.GroupId, err = url.PathUnescape(namespace); err != nil {
+		return GAV{}, errors.Join(errors.New("invalid purl: failed to decode namespace"), err)
+	}
+	if gav.ArtifactId, err = url.PathUnescape(name); err != nil {
+		return GAV{}, errors.Join(errors.New("invalid purl: failed to decode name"), err)
+	}
+	if gav.Version, err = url.PathUnescape(version); err != nil {
+		return GAV{}, errors.Join(errors.New("invalid purl: failed to decode version"), err)
+	}
+
+	return parseMavenCoordinate(gav.Coordinate())
+}
+
+// ParseCoordinate creates a new GAV struct from a Maven coordinate or a maven package url
+func ParseCoordinate(coordinate string) (GAV, error) {
+	if strings.HasPrefix(coordinate, "pkg:") {
+		return NewGAVFromPurl(coordinate)
+	}
+	return parseMavenCoordinate(coordinate)
+}
+
+// Purl returns the package url of the GAV
+func (gav *GAV) Purl() string {
+	purl := "pkg:maven/" + gav.GroupId + "/" + gav.ArtifactId
+	if gav.Version != "" {
+		purl += "@" + url.PathEscape(gav.Version)
+	}
+	return purl
+}
+
 func NewGAVIgnoreError(coordinate string) GAV {
 	gav, err := parseMavenCoordinate(coordinate)
 	if err != nil {
@@ -71,3 +122,8 @@ func isValidMavenCoordinate(coordinate string) bool {
 	}
 	return true
 }
+
+// RepositoryPath returns the directory of the version in a maven repository, only dots in the groupId are replaced
+func (gav *GAV) RepositoryPath() string {
+	return strings.ReplaceAll(gav.GroupId, ".", "/") + "/" + gav.ArtifactId + "/" + gav.Version
+}
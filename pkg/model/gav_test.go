@@ -41,3 +41,48 @@ func TestGavPath(t *testing.T) {
 		})
 	}
 }
+
+func TestParseCoordinate(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    GAV
+		wantErr bool
+	}{
+		{
+			input: "io.github.xanthic.cache:cache-api:0.6.2",
+			want:  GAV{GroupId: "io.github.xanthic.cache", ArtifactId: "cache-api", Version: "0.6.2"},
+		},
+		{
+			input: "pkg:maven/io.github.xanthic.cache/cache-api@0.6.2",
+			want:  GAV{GroupId: "io.github.xanthic.cache", ArtifactId: "cache-api", Version: "0.6.2"},
+		},
+		{
+			input: "pkg:maven/io.github.xanthic.cache/cache-api@0.6.2?type=jar&classifier=sources",
+			want:  GAV{GroupId: "io.github.xanthic.cache", ArtifactId: "cache-api", Version: "0.6.2"},
+		},
+		{
+			input: "pkg:maven/io.github.xanthic.cache/cache-api",
+			want:  GAV{GroupId: "io.github.xanthic.cache", ArtifactId: "cache-api"},
+		},
+		{
+			input:   "pkg:npm/left-pad@1.3.0",
+			wantErr: true,
+		},
+		{
+			input:   "pkg:maven/cache-api@0.6.2",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseCoordinate(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCoordinate(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseCoordinate(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package model
+
+import (
+	"maps"
+	"slices"
+	"time"
+)
+
+// History contains the reproducibility history of all versions of a project, recorded across index runs
+type History struct {
+	GroupID    string                     `json:"group_id"`
+	ArtifactID string                     `json:"artifact_id"`
+	Versions   map[string]*VersionHistory `json:"versions"`
+}
+
+// VersionHistory contains the changes of a single version, ordered from oldest to newest
+type VersionHistory struct {
+	FirstSeen time.Time             `json:"first_seen"`
+	Status    ReproducibilityStatus `json:"status"` // status of the latest index run
+	FileStats FileStats             `json:"file_stats"`
+	Entries   []HistoryEntry        `json:"entries"`
+}
+
+// HistoryEvent is the kind of change of a history entry
+type HistoryEvent string
+
+const (
+	HistoryEventBaseline         HistoryEvent = "baseline"           // the version was already indexed when the history was started
+	HistoryEventFirstSeen        HistoryEvent = "first-seen"         // the version was rebuilt for the first time
+	HistoryEventStatusChanged    HistoryEvent = "status-changed"     // the reproducibility status changed, e.g. after a rebuild with a fixed build spec
+	HistoryEventFileStatsChanged HistoryEvent = "file-stats-changed" // the number of (non-)reproducible files changed, but not the status
+	HistoryEventRemoved          HistoryEvent = "removed"            // the version is no longer part of the index
+)
+
+// HistoryEntry is a single change of a version
+type HistoryEntry struct {
+	Date              time.Time             `json:"date"`
+	Event             HistoryEvent          `json:"event"`
+	Status            ReproducibilityStatus `json:"status"`
+	PreviousStatus    ReproducibilityStatus `json:"previous_status,omitempty"`
+	FileStats         *FileStats            `json:"file_stats,omitempty"`
+	PreviousFileStats *FileStats            `json:"previous_file_stats,omitempty"`
+}
+
+// NewHistory creates an empty history of a project
+func NewHistory(groupId string, artifactId string) *History {
+	return &History{GroupID: groupId, ArtifactID: artifactId, Versions: make(map[string]*VersionHistory)}
+}
+
+// Record compares the versions of the previous and current index run and appends an entry for each change, returns true if at least one entry was added
+//
+// Versions of the previous run without history (e.g. the first run with a baseline) are recorded as baseline with their previous state, so they are not announced as new.
+// Removed versions keep their history, with the status unknown.
+func (h *History) Record(previous map[string]*Version, current map[string]*Version, date time.Time) bool {
+	if h.Versions == nil {
+		h.Versions = make(map[string]*VersionHistory)
+	}
+
+	changed := false
+	for _, version := range slices.Sorted(maps.Keys(current)) {
+		vh, ok := h.Versions[version]
+		if !ok {
+			state, event := current[version], HistoryEventFirstSeen
+			if previous[version] != nil {
+				state, event = previous[version], HistoryEventBaseline
+			}
+			stats := state.FileStats
+			vh = &VersionHistory{FirstSeen: date, Status: state.Status(), FileStats: stats}
+			vh.Entries = []HistoryEntry{{Date: date, Event: event, Status: vh.Status, FileStats: &stats}}
+			h.Versions[version] = vh
+			changed = true
+		}
+
+		if event := vh.event(current[version].Status(), current[version].FileStats); event != "" {
+			vh.record(event, current[version].Status(), current[version].FileStats, date)
+			changed = true
+		}
+	}
+
+	for _, version := range slices.Sorted(maps.Keys(h.Versions)) {
+		if _, ok := current[version]; ok || h.Versions[version].Status == StatusUnknown {
+			continue
+		}
+		h.Versions[version].record(HistoryEventRemoved, StatusUnknown, FileStats{}, date)
+		changed = true
+	}
+
+	return changed
+}
+
+// event returns the kind of change compared to the latest recorded state, an empty event if nothing changed
+func (vh *VersionHistory) event(status ReproducibilityStatus, stats FileStats) HistoryEvent {
+	switch {
+	case vh.Status != status:
+		return HistoryEventStatusChanged
+	case vh.FileStats != stats:
+		return HistoryEventFileStatsChanged
+	}
+	return ""
+}
+
+// record appends an entry and updates the latest recorded state
+func (vh *VersionHistory) record(event HistoryEvent, status ReproducibilityStatus, stats FileStats, date time.Time) {
+	previousStats := vh.FileStats
+	vh.Entries = append(vh.Entries, HistoryEntry{Date: date, Event: event, Status: status, PreviousStatus: vh.Status, FileStats: &stats, PreviousFileStats: &previousStats})
+	vh.Status, vh.FileStats = status, stats
+}
@@ -0,0 +1,65 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestHistoryRecord(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC)
+	}
+	reproducible := &Version{FileStats: FileStats{ModuleReproducibleFiles: 2}}
+	partial := &Version{FileStats: FileStats{ModuleReproducibleFiles: 1, ModuleNonReproducibleFiles: 1}}
+	moreFiles := &Version{FileStats: FileStats{ModuleReproducibleFiles: 3}}
+
+	history := NewHistory("com.example", "foo")
+
+	// first run with baseline, 1.0 was already indexed and regressed
+	if !history.Record(map[string]*Version{"1.0": reproducible}, map[string]*Version{"1.0": partial, "2.0": reproducible}, day(1)) {
+		t.Fatal("expected changes on first run")
+	}
+	// unchanged
+	if history.Record(map[string]*Version{"1.0": partial, "2.0": reproducible}, map[string]*Version{"1.0": partial, "2.0": reproducible}, day(2)) {
+		t.Error("expected no changes for identical runs")
+	}
+	// file stats changed, 1.0 removed
+	history.Record(map[string]*Version{"1.0": partial, "2.0": reproducible}, map[string]*Version{"2.0": moreFiles}, day(3))
+
+	events := func(version string) []string {
+		var result []string
+		for _, entry := range history.Versions[version].Entries {
+			result = append(result, entry.Date.Format(time.DateOnly)+" "+string(entry.Event)+" "+string(entry.PreviousStatus)+"->"+string(entry.Status))
+		}
+		return result
+	}
+	want := []string{
+		"2024-01-01 baseline ->reproducible",
+		"2024-01-01 status-changed reproducible->partially-reproducible",
+		"2024-01-03 removed partially-reproducible->unknown",
+	}
+	if diff := cmp.Diff(want, events("1.0")); diff != "" {
+		t.Errorf("1.0 history mismatch (-want +got):\n%s", diff)
+	}
+	want = []string{
+		"2024-01-01 first-seen ->reproducible",
+		"2024-01-03 file-stats-changed reproducible->reproducible",
+	}
+	if diff := cmp.Diff(want, events("2.0")); diff != "" {
+		t.Errorf("2.0 history mismatch (-want +got):\n%s", diff)
+	}
+	if entry := history.Versions["2.0"].Entries[1]; entry.PreviousFileStats.ModuleReproducibleFiles != 2 || entry.FileStats.ModuleReproducibleFiles != 3 {
+		t.Errorf("unexpected file stats %+v -> %+v", entry.PreviousFileStats, entry.FileStats)
+	}
+
+	// versions of the baseline are not announced as new
+	var feed []string
+	for _, entry := range NewFeedEntries(history, "") {
+		feed = append(feed, entry.Version+" "+string(entry.Event))
+	}
+	if diff := cmp.Diff([]string{"1.0 status-changed", "2.0 first-seen"}, feed); diff != "" {
+		t.Errorf("NewFeedEntries() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -1,25 +1,91 @@
 package model
 
+import (
+	"maps"
+	"slices"
+)
+
 type Repository struct {
 	Name string `json:"name"`
 	URL  string `json:"url"`
 }
 
 type Project struct {
-	RebuildProjectUrl string              `json:"rebuild_project_url,omitempty"`
-	GroupID           string              `json:"group_id"`
-	ArtifactID        string              `json:"artifact_id"`
-	Modules           []string            `json:"modules,omitempty"`
-	Versions          map[string]*Version `json:"versions"`
-	Latest            string              `json:"latest"`
+	RebuildProjectUrl  string              `json:"rebuild_project_url,omitempty"`
+	GroupID            string              `json:"group_id"`
+	ArtifactID         string              `json:"artifact_id"`
+	Modules            []string            `json:"modules,omitempty"`
+	Versions           map[string]*Version `json:"versions"`
+	OrderedVersions    []string            `json:"ordered_versions,omitempty"` // rebuilt versions, ordered from oldest to newest
+	Latest             string              `json:"latest"`
+	LatestRelease      string              `json:"latest_release,omitempty"`
+	LatestReproducible string              `json:"latest_reproducible,omitempty"`
+}
+
+// UpdateVersionOrder sorts the rebuilt versions and resolves the latest versions
+func (p *Project) UpdateVersionOrder() {
+	p.OrderedVersions, p.Latest, p.LatestRelease, p.LatestReproducible = resolveVersionOrder(p.Versions, p.Latest, p.LatestRelease)
 }
 
 type Dependency struct {
-	RebuildProjectUrl string              `json:"rebuild_project_url,omitempty"`
-	GroupID           string              `json:"group_id"`
-	ArtifactID        string              `json:"artifact_id"`
-	Versions          map[string]*Version `json:"versions"`
-	Latest            string              `json:"latest"`
+	RebuildProjectUrl  string              `json:"rebuild_project_url,omitempty"`
+	GroupID            string              `json:"group_id"`
+	ArtifactID         string              `json:"artifact_id"`
+	Versions           map[string]*Version `json:"versions"`
+	OrderedVersions    []string            `json:"ordered_versions,omitempty"` // rebuilt versions, ordered from oldest to newest
+	Latest             string              `json:"latest"`
+	LatestRelease      string              `json:"latest_release,omitempty"`
+	LatestReproducible string              `json:"latest_reproducible,omitempty"`
+}
+
+// UpdateVersionOrder sorts the rebuilt versions and resolves the latest versions
+func (d *Dependency) UpdateVersionOrder() {
+	d.OrderedVersions, d.Latest, d.LatestRelease, d.LatestReproducible = resolveVersionOrder(d.Versions, d.Latest, d.LatestRelease)
+}
+
+// Version aliases that can be used instead of a concrete version
+const (
+	VersionLatest             = "latest"              // latest published version
+	VersionLatestVerified     = "latest-verified"     // latest version with a rebuild result
+	VersionLatestReproducible = "latest-reproducible" // latest fully reproducible version
+)
+
+// IsVersionAlias returns true if the version is one of the supported version aliases
+func IsVersionAlias(version string) bool {
+	return version == VersionLatest || version == VersionLatestVerified || version == VersionLatestReproducible
+}
+
+// ResolveVersion resolves version aliases to a concrete version, other versions are returned as is
+// An empty string is returned if no version matches the alias.
+func (d *Dependency) ResolveVersion(version string) string {
+	switch version {
+	case VersionLatest:
+		return d.Latest
+	case VersionLatestVerified:
+		return MaxVersion(slices.Collect(maps.Keys(d.Versions))...)
+	case VersionLatestReproducible:
+		if d.LatestReproducible != "" {
+			return d.LatestReproducible
+		}
+		// index files generated before latest_reproducible was introduced
+		return LatestVersion(slices.Collect(maps.Keys(d.Versions)), func(v string) bool {
+			return d.Versions[v].Reproducible
+		})
+	}
+	return version
+}
+
+// resolveVersionOrder returns the ordered rebuilt versions, the latest (release) version across the published and rebuilt versions and the latest reproducible version
+func resolveVersionOrder(versions map[string]*Version, latest string, latestRelease string) (ordered []string, newLatest string, newLatestRelease string, latestReproducible string) {
+	ordered = slices.Collect(maps.Keys(versions))
+	SortVersions(ordered)
+
+	newLatest = MaxVersion(append([]string{latest}, ordered...)...)
+	newLatestRelease = LatestVersion(append([]string{latestRelease}, ordered...), IsReleaseVersion)
+	latestReproducible = LatestVersion(ordered, func(version string) bool {
+		return versions[version].Reproducible
+	})
+	return ordered, newLatest, newLatestRelease, latestReproducible
 }
 
 type Version struct {
@@ -30,6 +96,7 @@ type Version struct {
 	BuildJavaVersion string          `json:"build_java_version,omitempty"`
 	BuildOSName      string          `json:"build_os_name,omitempty"`
 	Reproducible     bool            `json:"reproducible"`
+	RebuildSpec      *RebuildSpec    `json:"rebuild_spec,omitempty"`
 	Files            map[string]File `json:"files,omitempty"`
 	FileStats        FileStats       `json:"file_stats,omitempty"`
 }
@@ -42,6 +109,74 @@ func (v *Version) SetModuleFileStats() {
 	v.FileStats.ModuleReproducibleFiles, v.FileStats.ModuleNonReproducibleFiles = countReproducibleFiles(v.Files)
 }
 
+// VersionDetails is a single version including the coordinate and the link to the rebuild project
+type VersionDetails struct {
+	GroupID           string `json:"group_id"`
+	ArtifactID        string `json:"artifact_id"`
+	ResolvedVersion   string `json:"version"`
+	RebuildProjectUrl string `json:"rebuild_project_url,omitempty"`
+	*Version
+}
+
+// Status returns the reproducibility status of the module files, falling back to all project files if the module has no files
+func (v *Version) Status() ReproducibilityStatus {
+	reproducible, nonReproducible := v.FileStats.Counts()
+	switch {
+	case reproducible > 0 && nonReproducible == 0:
+		return StatusReproducible
+	case reproducible > 0:
+		return StatusPartiallyReproducible
+	case nonReproducible > 0:
+		return StatusNotReproducible
+	}
+	return StatusUnknown
+}
+
+// NonReproducibleFiles returns the sorted names of all non-reproducible files
+func (v *Version) NonReproducibleFiles() []string {
+	var files []string
+	for name, file := range v.Files {
+		if !file.Reproducible {
+			files = append(files, name)
+		}
+	}
+	slices.Sort(files)
+	return files
+}
+
+type ReproducibilityStatus string
+
+const (
+	StatusReproducible          ReproducibilityStatus = "reproducible"
+	StatusPartiallyReproducible ReproducibilityStatus = "partially-reproducible"
+	StatusNotReproducible       ReproducibilityStatus = "not-reproducible"
+	StatusPending               ReproducibilityStatus = "pending" // the artifact is known, but the version was not rebuilt yet
+	StatusUnknown               ReproducibilityStatus = "unknown" // the artifact is not part of the index
+)
+
+// LookupResult is the reproducibility status of a single coordinate
+type LookupResult struct {
+	Coordinate           string                `json:"coordinate"` // the coordinate as requested
+	GroupID              string                `json:"group_id,omitempty"`
+	ArtifactID           string                `json:"artifact_id,omitempty"`
+	Version              string                `json:"version,omitempty"`
+	Status               ReproducibilityStatus `json:"status"`
+	RebuildProjectUrl    string                `json:"rebuild_project_url,omitempty"`
+	FileStats            *FileStats            `json:"file_stats,omitempty"`
+	NonReproducibleFiles []string              `json:"non_reproducible_files,omitempty"`
+	Error                string                `json:"error,omitempty"`
+}
+
+// RebuildSpec contains the instructions that were used to rebuild a version (from the .buildspec file)
+type RebuildSpec struct {
+	GitRepo string `json:"git_repo,omitempty"`
+	GitTag  string `json:"git_tag,omitempty"`
+	Tool    string `json:"tool,omitempty"`
+	JDK     string `json:"jdk,omitempty"`
+	Newline string `json:"newline,omitempty"`
+	Command string `json:"command,omitempty"`
+}
+
 type FileStats struct {
 	TotalReproducibleFiles     int `json:"total_reproducible"`
 	TotalNonReproducibleFiles  int `json:"total_non_reproducible"`
@@ -49,12 +184,29 @@ type FileStats struct {
 	ModuleNonReproducibleFiles int `json:"module_non_reproducible"`
 }
 
+// Counts returns the reproducible and non-reproducible file count of the module, falling back to all project files if the module has no files
+func (s FileStats) Counts() (reproducible int, nonReproducible int) {
+	if s.ModuleReproducibleFiles+s.ModuleNonReproducibleFiles == 0 {
+		return s.TotalReproducibleFiles, s.TotalNonReproducibleFiles
+	}
+	return s.ModuleReproducibleFiles, s.ModuleNonReproducibleFiles
+}
+
 type File struct {
 	Size         string `json:"size,omitempty"`
 	Checksum     string `json:"checksum,omitempty"`
 	Reproducible bool   `json:"reproducible"`
+	// details for non-reproducible files
+	Reason            string `json:"reason,omitempty"`
+	DiffoscopeCommand string `json:"diffoscope_command,omitempty"`
+	DiffoscopeUrl     string `json:"diffoscope_url,omitempty"`
 }
 
+const (
+	FileReasonDifferent   = "rebuilt file differs from reference"
+	FileReasonNotCompared = "file was not compared"
+)
+
 func countReproducibleFiles(files map[string]File) (reproducibleCount, nonReproducibleCount int) {
 	for _, file := range files {
 		if file.Reproducible {
@@ -2,22 +2,155 @@ package model
 
 import (
 	"encoding/xml"
+	"regexp"
+	"strings"
 )
 
 type PomProject struct {
 	XMLName              xml.Name                `xml:"project"`
+	GroupId              string                  `xml:"groupId"`
+	ArtifactId           string                  `xml:"artifactId"`
+	Version              string                  `xml:"version"`
 	Packaging            string                  `xml:"packaging"`
+	Parent               *PomParent              `xml:"parent"`
+	Modules              []string                `xml:"modules>module"`
+	Properties           PomProperties           `xml:"properties"`
 	Dependencies         []PomDependency         `xml:"dependencies>dependency"`
 	DependencyManagement PomDependencyManagement `xml:"dependencyManagement"`
 }
 
+type PomParent struct {
+	GroupId    string `xml:"groupId"`
+	ArtifactId string `xml:"artifactId"`
+	Version    string `xml:"version"`
+}
+
 type PomDependencyManagement struct {
 	Dependencies []PomDependency `xml:"dependencies>dependency"`
 }
 
 type PomDependency struct {
+	GroupId    string         `xml:"groupId"`
+	ArtifactId string         `xml:"artifactId"`
+	Version    string         `xml:"version"`
+	Type       string         `xml:"type,omitempty"`
+	Classifier string         `xml:"classifier,omitempty"`
+	Scope      string         `xml:"scope,omitempty"`
+	Optional   string         `xml:"optional,omitempty"`
+	Exclusions []PomExclusion `xml:"exclusions>exclusion"`
+}
+
+// GAV returns the coordinate of the dependency
+func (d PomDependency) GAV() GAV {
+	return GAV{GroupId: d.GroupId, ArtifactId: d.ArtifactId, Version: d.Version}
+}
+
+// IsOptional returns true if the dependency is marked as optional
+func (d PomDependency) IsOptional() bool {
+	return d.Optional == "true"
+}
+
+type PomExclusion struct {
 	GroupId    string `xml:"groupId"`
 	ArtifactId string `xml:"artifactId"`
-	Version    string `xml:"version"`
-	Scope      string `xml:"scope,omitempty"`
+}
+
+// Matches returns true if the exclusion matches the given coordinate (supports * as wildcard)
+func (e PomExclusion) Matches(groupId string, artifactId string) bool {
+	return (e.GroupId == "*" || e.GroupId == groupId) && (e.ArtifactId == "*" || e.ArtifactId == artifactId)
+}
+
+// PomProperties contains the properties of a pom, the element names are used as keys
+type PomProperties map[string]string
+
+func (p *PomProperties) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	*p = PomProperties{}
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			var value string
+			if err = d.DecodeElement(&value, &t); err != nil {
+				return err
+			}
+			(*p)[t.Name.Local] = value
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+var pomPlaceholder = regexp.MustCompile(`\$\{([^}]+)}`)
+
+// Interpolate replaces ${...} placeholders in the project coordinates and dependencies with the project properties
+func (p *PomProject) Interpolate() {
+	properties := map[string]string{
+		"project.groupId":    p.GroupId,
+		"pom.groupId":        p.GroupId,
+		"groupId":            p.GroupId,
+		"project.artifactId": p.ArtifactId,
+		"pom.artifactId":     p.ArtifactId,
+		"artifactId":         p.ArtifactId,
+		"project.version":    p.Version,
+		"pom.version":        p.Version,
+		"version":            p.Version,
+	}
+	if p.Parent != nil {
+		properties["project.parent.groupId"] = p.Parent.GroupId
+		properties["parent.groupId"] = p.Parent.GroupId
+		properties["project.parent.version"] = p.Parent.Version
+		properties["parent.version"] = p.Parent.Version
+	}
+	for k, v := range p.Properties {
+		properties[k] = v
+	}
+	resolve := func(value string) string {
+		return interpolate(value, properties, 0)
+	}
+
+	p.GroupId = resolve(p.GroupId)
+	p.ArtifactId = resolve(p.ArtifactId)
+	p.Version = resolve(p.Version)
+	p.Packaging = resolve(p.Packaging)
+	for i := range p.Modules {
+		p.Modules[i] = resolve(p.Modules[i])
+	}
+	for i := range p.Dependencies {
+		p.Dependencies[i].interpolate(resolve)
+	}
+	for i := range p.DependencyManagement.Dependencies {
+		p.DependencyManagement.Dependencies[i].interpolate(resolve)
+	}
+}
+
+func (d *PomDependency) interpolate(resolve func(string) string) {
+	d.GroupId = resolve(d.GroupId)
+	d.ArtifactId = resolve(d.ArtifactId)
+	d.Version = resolve(d.Version)
+	d.Type = resolve(d.Type)
+	d.Classifier = resolve(d.Classifier)
+	d.Scope = resolve(d.Scope)
+	d.Optional = resolve(d.Optional)
+	for i := range d.Exclusions {
+		d.Exclusions[i].GroupId = resolve(d.Exclusions[i].GroupId)
+		d.Exclusions[i].ArtifactId = resolve(d.Exclusions[i].ArtifactId)
+	}
+}
+
+func interpolate(value string, properties map[string]string, depth int) string {
+	if depth > 10 || !strings.Contains(value, "${") {
+		return value // nothing to replace or cyclic reference
+	}
+
+	return pomPlaceholder.ReplaceAllStringFunc(value, func(match string) string {
+		ref, ok := properties[pomPlaceholder.FindStringSubmatch(match)[1]]
+		if !ok {
+			return match
+		}
+		return interpolate(ref, properties, depth+1)
+	})
 }
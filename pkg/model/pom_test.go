@@ -0,0 +1,72 @@
+package model
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPomInterpolate(t *testing.T) {
+	input := `<project>
+  <parent>
+    <groupId>com.example</groupId>
+    <artifactId>parent</artifactId>
+    <version>1.0</version>
+  </parent>
+  <groupId>com.example</groupId>
+  <artifactId>bom</artifactId>
+  <version>2.0</version>
+  <packaging>pom</packaging>
+  <modules>
+    <module>core</module>
+  </modules>
+  <properties>
+    <lib.version>${project.version}</lib.version>
+    <lib.classifier>jdk11</lib.classifier>
+  </properties>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>${project.groupId}</groupId>
+        <artifactId>lib</artifactId>
+        <version>${lib.version}</version>
+        <classifier>${lib.classifier}</classifier>
+        <optional>true</optional>
+        <exclusions>
+          <exclusion>
+            <groupId>${project.parent.groupId}</groupId>
+            <artifactId>*</artifactId>
+          </exclusion>
+        </exclusions>
+      </dependency>
+      <dependency>
+        <groupId>com.example</groupId>
+        <artifactId>unknown</artifactId>
+        <version>${unknown.version}</version>
+        <type>test-jar</type>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+</project>`
+
+	var pom PomProject
+	if err := xml.Unmarshal([]byte(input), &pom); err != nil {
+		t.Fatal(err)
+	}
+	pom.Interpolate()
+
+	expected := []PomDependency{
+		{GroupId: "com.example", ArtifactId: "lib", Version: "2.0", Classifier: "jdk11", Optional: "true", Exclusions: []PomExclusion{{GroupId: "com.example", ArtifactId: "*"}}},
+		{GroupId: "com.example", ArtifactId: "unknown", Version: "${unknown.version}", Type: "test-jar"}, // unresolved placeholders are kept
+	}
+	if diff := cmp.Diff(expected, pom.DependencyManagement.Dependencies); diff != "" {
+		t.Errorf("unexpected dependencies (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"core"}, pom.Modules); diff != "" {
+		t.Errorf("unexpected modules (-want +got):\n%s", diff)
+	}
+	if !pom.DependencyManagement.Dependencies[0].IsOptional() {
+		t.Errorf("expected dependency to be optional")
+	}
+}
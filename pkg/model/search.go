@@ -0,0 +1,55 @@
+package model
+
+// Search entry types
+const (
+	SearchTypeProject = "project"
+	SearchTypeMaven   = "maven"
+)
+
+// SearchIndex lists all indexed projects and artifacts of a registry
+type SearchIndex struct {
+	Entries []SearchEntry `json:"entries"`
+}
+
+// SearchEntry is a single project or artifact, the build details refer to the latest rebuilt version
+type SearchEntry struct {
+	Type               string                `json:"type"`
+	GroupID            string                `json:"group_id"`
+	ArtifactID         string                `json:"artifact_id"`
+	Name               string                `json:"name,omitempty"` // rebuild project name
+	Latest             string                `json:"latest,omitempty"`
+	LatestVerified     string                `json:"latest_verified,omitempty"`
+	LatestReproducible string                `json:"latest_reproducible,omitempty"`
+	BuildTool          string                `json:"build_tool,omitempty"`
+	Reproducible       bool                  `json:"reproducible"`
+	Status             ReproducibilityStatus `json:"status"`
+	Versions           int                   `json:"versions"`
+}
+
+// NewSearchEntry creates the search entry of a project or artifact index
+func NewSearchEntry(entryType string, data *Dependency) SearchEntry {
+	entry := SearchEntry{
+		Type:               entryType,
+		GroupID:            data.GroupID,
+		ArtifactID:         data.ArtifactID,
+		Latest:             data.Latest,
+		LatestReproducible: data.LatestReproducible,
+		Status:             StatusUnknown,
+		Versions:           len(data.Versions),
+	}
+
+	entry.LatestVerified = data.ResolveVersion(VersionLatestVerified)
+	if v, ok := data.Versions[entry.LatestVerified]; ok {
+		entry.Name = v.Project
+		entry.BuildTool = v.BuildTool
+		entry.Reproducible = v.Reproducible
+		entry.Status = v.Status()
+	}
+
+	return entry
+}
+
+// Coordinate returns groupId:artifactId
+func (e SearchEntry) Coordinate() string {
+	return e.GroupID + ":" + e.ArtifactID
+}
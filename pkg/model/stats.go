@@ -0,0 +1,53 @@
+package model
+
+import "math"
+
+// Stats contains the aggregated reproducibility statistics of all projects of a registry
+type Stats struct {
+	Projects                 int                   `json:"projects"`
+	Versions                 int                   `json:"versions"`
+	Files                    int                   `json:"files"`
+	Overall                  StatsRate             `json:"overall"`
+	BuildTools               map[string]*StatsRate `json:"build_tools"` // by BuildTool, e.g. mvn or gradle
+	JDKs                     map[string]*StatsRate `json:"jdks"`        // by major version of BuildJavaVersion, e.g. 17
+	OSNames                  map[string]*StatsRate `json:"os_names"`    // by BuildOSName, e.g. Linux
+	NonReproducibleFileTypes []FileTypeCount       `json:"non_reproducible_file_types"`
+}
+
+// StatsRate is the number of (reproducible) versions and files, versions are reproducible if all files are reproducible
+type StatsRate struct {
+	Versions             int     `json:"versions"`
+	ReproducibleVersions int     `json:"reproducible_versions"`
+	VersionRate          float64 `json:"version_rate"` // percentage of reproducible versions
+	Files                int     `json:"files"`
+	ReproducibleFiles    int     `json:"reproducible_files"`
+	FileRate             float64 `json:"file_rate"` // percentage of reproducible files
+}
+
+// FileTypeCount is the number of non-reproducible files of a file type, e.g. jar or sources.jar
+type FileTypeCount struct {
+	Type  string `json:"type"`
+	Count int    `json:"count"`
+}
+
+// Add counts the version and its files
+func (r *StatsRate) Add(version *Version) {
+	r.Versions++
+	if version.Status() == StatusReproducible {
+		r.ReproducibleVersions++
+	}
+	reproducible, nonReproducible := version.FileStats.Counts()
+	r.Files += reproducible + nonReproducible
+	r.ReproducibleFiles += reproducible
+
+	r.VersionRate = percentage(r.ReproducibleVersions, r.Versions)
+	r.FileRate = percentage(r.ReproducibleFiles, r.Files)
+}
+
+// percentage returns the percentage rounded to two decimals
+func percentage(value int, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(value)*10000/float64(total)) / 100
+}
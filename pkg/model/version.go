@@ -0,0 +1,323 @@
+package model
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ComparableVersion implements the version ordering of Maven (org.apache.maven.artifact.versioning.ComparableVersion)
+//
+// Versions are split into numeric and string items, separated by '.', '-' or the transition between digits and characters.
+// Well-known qualifiers are ordered as follows: alpha < beta < milestone < rc = cr < snapshot < "" = final = ga = release < sp
+// Unknown qualifiers are considered after known qualifiers and are compared lexically (case-insensitive).
+type ComparableVersion struct {
+	value string
+	items listItem
+}
+
+func NewComparableVersion(version string) ComparableVersion {
+	return ComparableVersion{
+		value: version,
+		items: parseVersion(version),
+	}
+}
+
+func (v ComparableVersion) String() string {
+	return v.value
+}
+
+// Compare returns -1 if v is older than o, 0 if both are equal and 1 if v is newer than o
+func (v ComparableVersion) Compare(o ComparableVersion) int {
+	return v.items.compare(o.items)
+}
+
+// CompareVersions compares two version strings using the Maven version ordering
+func CompareVersions(a string, b string) int {
+	return NewComparableVersion(a).Compare(NewComparableVersion(b))
+}
+
+// SortVersions sorts the given versions in ascending order
+func SortVersions(versions []string) {
+	slices.SortStableFunc(versions, CompareVersions)
+}
+
+// MaxVersion returns the newest of the given versions, empty versions are ignored
+func MaxVersion(versions ...string) string {
+	return LatestVersion(versions, nil)
+}
+
+// LatestVersion returns the newest version that matches the filter (nil matches all versions)
+func LatestVersion(versions []string, filter func(version string) bool) string {
+	latest := ""
+	var latestVersion ComparableVersion
+	for _, version := range versions {
+		if version == "" || (filter != nil && !filter(version)) {
+			continue
+		}
+
+		current := NewComparableVersion(version)
+		if latest == "" || current.Compare(latestVersion) > 0 {
+			latest = version
+			latestVersion = current
+		}
+	}
+	return latest
+}
+
+// IsReleaseVersion returns false for snapshots and pre-releases (alpha, beta, milestone and release candidates)
+func IsReleaseVersion(version string) bool {
+	return !containsQualifier(parseVersion(version), func(qualifier string) bool {
+		return qualifier != releaseQualifierIndex && qualifier != spQualifierIndex && len(qualifier) == 1
+	})
+}
+
+func containsQualifier(items listItem, match func(qualifier string) bool) bool {
+	for _, it := range items {
+		switch i := it.(type) {
+		case stringItem:
+			if match(comparableQualifier(string(i))) {
+				return true
+			}
+		case listItem:
+			if containsQualifier(i, match) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// item is a single version component
+type item interface {
+	// compare returns the ordering of the item against the other item, other may be nil
+	compare(other item) int
+	isNull() bool
+}
+
+// intItem is a numeric version component, stored without leading zeros to support arbitrary large numbers
+type intItem string
+
+func (i intItem) isNull() bool {
+	return i == "" || i == "0"
+}
+
+func (i intItem) compare(other item) int {
+	switch o := other.(type) {
+	case nil:
+		if i.isNull() {
+			return 0
+		}
+		return 1
+	case intItem:
+		if len(i) != len(o) {
+			return cmp.Compare(len(i), len(o))
+		}
+		return strings.Compare(string(i), string(o))
+	case stringItem:
+		return 1 // 1.1 > 1-sp
+	case listItem:
+		return 1 // 1.1 > 1-1
+	}
+	return 0
+}
+
+// stringItem is a qualifier
+type stringItem string
+
+var qualifiers = []string{"alpha", "beta", "milestone", "rc", "snapshot", "", "sp"}
+
+var qualifierAliases = map[string]string{
+	"ga":      "",
+	"final":   "",
+	"release": "",
+	"cr":      "rc",
+}
+
+var (
+	releaseQualifierIndex = strconv.Itoa(slices.Index(qualifiers, ""))
+	spQualifierIndex      = strconv.Itoa(slices.Index(qualifiers, "sp"))
+)
+
+func newStringItem(value string, followedByDigit bool) stringItem {
+	if followedByDigit && len(value) == 1 {
+		// a1 = alpha-1, b1 = beta-1, m1 = milestone-1
+		switch value {
+		case "a":
+			value = "alpha"
+		case "b":
+			value = "beta"
+		case "m":
+			value = "milestone"
+		}
+	}
+	if alias, ok := qualifierAliases[value]; ok {
+		value = alias
+	}
+	return stringItem(value)
+}
+
+// comparableQualifier returns a string that sorts known qualifiers in their defined order and unknown qualifiers after all known ones
+func comparableQualifier(qualifier string) string {
+	if i := slices.Index(qualifiers, qualifier); i != -1 {
+		return strconv.Itoa(i)
+	}
+	return strconv.Itoa(len(qualifiers)) + "-" + qualifier
+}
+
+func (s stringItem) isNull() bool {
+	return comparableQualifier(string(s)) == releaseQualifierIndex
+}
+
+func (s stringItem) compare(other item) int {
+	switch o := other.(type) {
+	case nil:
+		// 1-rc < 1, 1-ga > 1
+		return strings.Compare(comparableQualifier(string(s)), releaseQualifierIndex)
+	case intItem:
+		return -1 // 1.any < 1.1
+	case stringItem:
+		return strings.Compare(comparableQualifier(string(s)), comparableQualifier(string(o)))
+	case listItem:
+		return -1 // 1.any < 1-1
+	}
+	return 0
+}
+
+// listItem is a sub-version, started by '-' or the transition between digits and characters
+type listItem []item
+
+func (l listItem) isNull() bool {
+	return len(l) == 0
+}
+
+func (l listItem) compare(other item) int {
+	switch o := other.(type) {
+	case nil:
+		for _, i := range l {
+			if result := i.compare(nil); result != 0 {
+				return result
+			}
+		}
+		return 0
+	case intItem:
+		return -1 // 1-1 < 1.0.x
+	case stringItem:
+		return 1 // 1-1 > 1-sp
+	case listItem:
+		for idx := 0; idx < len(l) || idx < len(o); idx++ {
+			var left, right item
+			if idx < len(l) {
+				left = l[idx]
+			}
+			if idx < len(o) {
+				right = o[idx]
+			}
+
+			var result int
+			if left == nil {
+				if right != nil {
+					result = -1 * right.compare(nil)
+				}
+			} else {
+				result = left.compare(right)
+			}
+			if result != 0 {
+				return result
+			}
+		}
+		return 0
+	}
+	return 0
+}
+
+// normalize removes trailing null items (0, "", empty list)
+func (l listItem) normalize() listItem {
+	for i := len(l) - 1; i >= 0; i-- {
+		if l[i].isNull() {
+			l = slices.Delete(l, i, i+1)
+		} else if _, isList := l[i].(listItem); !isList {
+			break
+		}
+	}
+	return l
+}
+
+// parseVersion splits a version into its items, lists are tracked as a stack of pointers to allow normalizing nested lists before adding them to their parent
+func parseVersion(version string) listItem {
+	version = strings.ToLower(version)
+
+	type frame struct {
+		items  listItem
+		parent *frame
+	}
+	root := &frame{}
+	current := root
+	push := func() {
+		current = &frame{parent: current}
+	}
+	// nested lists are appended to their parent when they are complete, the stack keeps the order of items intact
+	pop := func() {
+		child := current
+		current = child.parent
+		current.items = append(current.items, child.items.normalize())
+	}
+
+	runes := []rune(version)
+	isDigit := false
+	startIndex := 0
+	for i, c := range runes {
+		switch {
+		case c == '.':
+			if i == startIndex {
+				current.items = append(current.items, intItem("0"))
+			} else {
+				current.items = append(current.items, parseItem(isDigit, string(runes[startIndex:i])))
+			}
+			startIndex = i + 1
+		case c == '-':
+			if i == startIndex {
+				current.items = append(current.items, intItem("0"))
+			} else {
+				current.items = append(current.items, parseItem(isDigit, string(runes[startIndex:i])))
+			}
+			startIndex = i + 1
+			push()
+		case unicode.IsDigit(c):
+			if !isDigit && i > startIndex {
+				current.items = append(current.items, newStringItem(string(runes[startIndex:i]), true))
+				startIndex = i
+				push()
+			}
+			isDigit = true
+		default:
+			if isDigit && i > startIndex {
+				current.items = append(current.items, parseItem(true, string(runes[startIndex:i])))
+				startIndex = i
+				push()
+			}
+			isDigit = false
+		}
+	}
+	if len(runes) > startIndex {
+		current.items = append(current.items, parseItem(isDigit, string(runes[startIndex:])))
+	}
+
+	for current != root {
+		pop()
+	}
+	return root.items.normalize()
+}
+
+func parseItem(isDigit bool, value string) item {
+	if isDigit {
+		value = strings.TrimLeft(value, "0")
+		if value == "" {
+			value = "0"
+		}
+		return intItem(value)
+	}
+	return newStringItem(value, false)
+}
@@ -0,0 +1,110 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// test cases from https://github.com/apache/maven/blob/maven-3.8.8/maven-artifact/src/test/java/org/apache/maven/artifact/versioning/ComparableVersionTest.java
+var versionsQualifier = []string{"1-alpha2snapshot", "1-alpha2", "1-alpha-123", "1-beta-2", "1-beta123", "1-m2", "1-m11", "1-rc", "1-cr2",
+	"1-rc123", "1-SNAPSHOT", "1", "1-sp", "1-sp2", "1-sp123", "1-abc", "1-def", "1-pom-1", "1-1-snapshot",
+	"1-1", "1-2", "1-123"}
+
+var versionsNumber = []string{"2.0", "2-1", "2.0.a", "2.0.0.a", "2.0.2", "2.0.123", "2.1.0", "2.1-a", "2.1b", "2.1-c",
+	"2.1-1", "2.1.0.1", "2.2", "2.123", "11.a2", "11.a11", "11.b2", "11.b11", "11.m2", "11.m11", "11", "11.a",
+	"11b", "11c", "11m"}
+
+func TestVersionOrdering(t *testing.T) {
+	for _, versions := range [][]string{versionsQualifier, versionsNumber} {
+		for i := 1; i < len(versions); i++ {
+			low := versions[i-1]
+			for j := i; j < len(versions); j++ {
+				high := versions[j]
+				if CompareVersions(low, high) >= 0 {
+					t.Errorf("expected %q < %q", low, high)
+				}
+				if CompareVersions(high, low) <= 0 {
+					t.Errorf("expected %q > %q", high, low)
+				}
+			}
+		}
+	}
+}
+
+func TestVersionEquality(t *testing.T) {
+	tests := [][]string{
+		{"1", "1", "1.0", "1.0.0", "1-0", "1.0-0", "1-ga", "1.0-final", "1-release", "1.0.0-ga"},
+		{"1a", "1-a", "1.0-a", "1.0.0-a"},
+		{"1x", "1-x", "1.0-x", "1.0.0-x"},
+		{"1a1", "1-alpha-1"},
+		{"1b2", "1-beta-2"},
+		{"1m3", "1-milestone-3"},
+		{"1rc4", "1-rc-4"},
+		{"1cr5", "1-rc-5"},
+		{"1ga", "1GA", "1final", "1FINAL", "1release", "1RELEASE", "1"},
+		{"1cr", "1rc"},
+	}
+
+	for _, versions := range tests {
+		for _, other := range versions[1:] {
+			if CompareVersions(versions[0], other) != 0 {
+				t.Errorf("expected %q == %q", versions[0], other)
+			}
+		}
+	}
+}
+
+func TestSortVersions(t *testing.T) {
+	versions := []string{"1.10.0", "1.2.0", "1.10.0-rc1", "1.9.1", "2.0.0-SNAPSHOT", "1.2.0-beta"}
+	SortVersions(versions)
+
+	if diff := cmp.Diff([]string{"1.2.0-beta", "1.2.0", "1.9.1", "1.10.0-rc1", "1.10.0", "2.0.0-SNAPSHOT"}, versions); diff != "" {
+		t.Errorf("SortVersions() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestIsReleaseVersion(t *testing.T) {
+	tests := map[string]bool{
+		"1.0":            true,
+		"1.0.Final":      true,
+		"1.0-sp1":        true,
+		"1.0-jre":        true,
+		"2.0.0-SNAPSHOT": false,
+		"1.0-alpha-1":    false,
+		"1.0-M2":         false,
+		"1.0-RC1":        false,
+		"1.0.0-beta.3":   false,
+	}
+
+	for version, want := range tests {
+		if got := IsReleaseVersion(version); got != want {
+			t.Errorf("IsReleaseVersion(%q) = %v, want %v", version, got, want)
+		}
+	}
+}
+
+func TestUpdateVersionOrder(t *testing.T) {
+	project := Project{
+		Versions: map[string]*Version{
+			"1.10.0":    {Reproducible: false},
+			"1.9.0":     {Reproducible: true},
+			"2.0.0-RC1": {Reproducible: true},
+		},
+		Latest: "2.1.0-SNAPSHOT",
+	}
+	project.UpdateVersionOrder()
+
+	if diff := cmp.Diff([]string{"1.9.0", "1.10.0", "2.0.0-RC1"}, project.OrderedVersions); diff != "" {
+		t.Errorf("OrderedVersions mismatch (-want +got):\n%s", diff)
+	}
+	if project.Latest != "2.1.0-SNAPSHOT" {
+		t.Errorf("Latest = %q, want %q", project.Latest, "2.1.0-SNAPSHOT")
+	}
+	if project.LatestRelease != "1.10.0" {
+		t.Errorf("LatestRelease = %q, want %q", project.LatestRelease, "1.10.0")
+	}
+	if project.LatestReproducible != "2.0.0-RC1" {
+		t.Errorf("LatestReproducible = %q, want %q", project.LatestReproducible, "2.0.0-RC1")
+	}
+}
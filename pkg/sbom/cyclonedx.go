@@ -0,0 +1,200 @@
+package sbom
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/model"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/service"
+)
+
+var ErrInvalidCycloneDX = errors.New("invalid CycloneDX document")
+
+// elements that follow externalReferences / properties in a CycloneDX xml component, the order is defined by the xml schema
+var (
+	cycloneDXExternalReferencesBefore = []string{"properties", "components", "evidence", "releaseNotes", "modelCard", "data", "cryptoProperties", "signature"}
+	cycloneDXPropertiesBefore         = []string{"components", "evidence", "releaseNotes", "modelCard", "data", "cryptoProperties", "signature"}
+)
+
+// CycloneDX is a CycloneDX bom in json or xml format
+//
+// The document is kept as generic tree, all fields that are not touched by Enrich are written back as they were read (in their original order).
+type CycloneDX struct {
+	Format Format
+	json   *jsonObject
+	xml    *xmlDocument
+}
+
+// ParseCycloneDX parses a CycloneDX bom, the format (json or xml) is detected from the content
+func ParseCycloneDX(content []byte) (*CycloneDX, error) {
+	bom := &CycloneDX{Format: DetectFormat(content)}
+
+	if bom.Format == FormatXML {
+		doc, err := parseXMLDocument(content)
+		if err != nil {
+			return nil, errors.Join(ErrInvalidCycloneDX, err)
+		}
+		if doc.Root.Name.Local != "bom" {
+			return nil, errors.Join(ErrInvalidCycloneDX, errors.New("root element is not a bom"))
+		}
+		bom.xml = doc
+		return bom, nil
+	}
+
+	doc, err := parseJSONObject(content)
+	if err != nil {
+		return nil, errors.Join(ErrInvalidCycloneDX, err)
+	}
+	if doc.get("bomFormat") != "CycloneDX" {
+		return nil, errors.Join(ErrInvalidCycloneDX, errors.New("bomFormat is not CycloneDX"))
+	}
+	bom.json = doc
+	return bom, nil
+}
+
+// Bytes returns the encoded bom, in the same format as it was read
+func (b *CycloneDX) Bytes() ([]byte, error) {
+	if b.Format == FormatXML {
+		return b.xml.bytes(), nil
+	}
+
+	return encodeJSON(b.json)
+}
+
+// Purls returns the maven package urls of the described component (metadata.component) and all components, including nested components
+func (b *CycloneDX) Purls() []string {
+	var purls []string
+	b.walkComponents(func(purl string, _ func(model.LookupResult)) {
+		purls = append(purls, purl)
+	})
+	return purls
+}
+
+// Enrich adds the reproducibility properties and a reference to the rebuild project to the described component and all components with a lookup result (keyed by purl)
+//
+// Returns the number of enriched components.
+func (b *CycloneDX) Enrich(results map[string]service.VersionStatus) int {
+	count := 0
+	b.walkComponents(func(purl string, enrich func(model.LookupResult)) {
+		if result, ok := results[purl]; ok && result.Result.Error == "" {
+			enrich(result.Result)
+			count++
+		}
+	})
+	return count
+}
+
+// walkComponents calls fn for all components with a maven purl, enrich modifies the component
+func (b *CycloneDX) walkComponents(fn func(purl string, enrich func(model.LookupResult))) {
+	if b.Format == FormatXML {
+		for _, metadata := range b.xml.Root.elements("metadata") {
+			for _, component := range metadata.elements("component") {
+				walkCycloneDXXMLComponent(component, fn)
+			}
+		}
+		walkCycloneDXXMLComponents(b.xml.Root, fn)
+		return
+	}
+
+	if component := asObject(asObject(b.json.get("metadata")).get("component")); component != nil {
+		walkCycloneDXJSONComponent(component, fn)
+	}
+	walkCycloneDXJSONComponents(b.json, fn)
+}
+
+func walkCycloneDXJSONComponents(parent *jsonObject, fn func(purl string, enrich func(model.LookupResult))) {
+	for _, c := range asSlice(parent.get("components")) {
+		if component := asObject(c); component != nil {
+			walkCycloneDXJSONComponent(component, fn)
+		}
+	}
+}
+
+func walkCycloneDXJSONComponent(component *jsonObject, fn func(purl string, enrich func(model.LookupResult))) {
+	if purl, _ := component.get("purl").(string); strings.HasPrefix(purl, "pkg:maven/") {
+		fn(purl, func(result model.LookupResult) {
+			enrichCycloneDXJSONComponent(component, result)
+		})
+	}
+	walkCycloneDXJSONComponents(component, fn)
+}
+
+func enrichCycloneDXJSONComponent(component *jsonObject, result model.LookupResult) {
+	// replace properties of previous runs
+	properties := make([]any, 0)
+	for _, p := range asSlice(component.get("properties")) {
+		if prop := asObject(p); prop != nil {
+			if name, _ := prop.get("name").(string); strings.HasPrefix(name, PropertyPrefix) {
+				continue
+			}
+		}
+		properties = append(properties, p)
+	}
+	for _, p := range resultProperties(result) {
+		property := newJSONObject()
+		property.set("name", p.Name)
+		property.set("value", p.Value)
+		properties = append(properties, property)
+	}
+	component.set("properties", properties)
+
+	if result.RebuildProjectUrl == "" {
+		return
+	}
+	references := asSlice(component.get("externalReferences"))
+	for _, r := range references {
+		if ref := asObject(r); ref != nil && ref.get("url") == result.RebuildProjectUrl {
+			return
+		}
+	}
+	reference := newJSONObject()
+	reference.set("type", "build-system")
+	reference.set("url", result.RebuildProjectUrl)
+	reference.set("comment", RebuildReferenceComment)
+	component.set("externalReferences", append(references, reference))
+}
+
+func walkCycloneDXXMLComponents(parent *xmlElement, fn func(purl string, enrich func(model.LookupResult))) {
+	for _, components := range parent.elements("components") {
+		for _, component := range components.elements("component") {
+			walkCycloneDXXMLComponent(component, fn)
+		}
+	}
+}
+
+func walkCycloneDXXMLComponent(component *xmlElement, fn func(purl string, enrich func(model.LookupResult))) {
+	if purl := component.element("purl"); purl != nil && strings.HasPrefix(purl.text(), "pkg:maven/") {
+		fn(purl.text(), func(result model.LookupResult) {
+			enrichCycloneDXXMLComponent(component, result)
+		})
+	}
+	walkCycloneDXXMLComponents(component, fn)
+}
+
+func enrichCycloneDXXMLComponent(component *xmlElement, result model.LookupResult) {
+	// replace properties of previous runs
+	properties := component.getOrInsertElement("properties", cycloneDXPropertiesBefore)
+	properties.removeElements(func(element *xmlElement) bool {
+		return strings.HasPrefix(element.attr("name"), PropertyPrefix)
+	})
+	for _, p := range resultProperties(result) {
+		property := newXMLElement(properties, "property", p.Value)
+		property.setAttr("name", p.Name)
+		properties.insertElement(property, nil)
+	}
+
+	if result.RebuildProjectUrl == "" {
+		return
+	}
+	references := component.getOrInsertElement("externalReferences", cycloneDXExternalReferencesBefore)
+	for _, ref := range references.elements("reference") {
+		if url := ref.element("url"); url != nil && url.text() == result.RebuildProjectUrl {
+			return
+		}
+	}
+	reference := newXMLElement(references, "reference", "")
+	reference.setAttr("type", "build-system")
+	reference.insertElement(newXMLElement(reference, "url", result.RebuildProjectUrl), nil)
+	reference.insertElement(newXMLElement(reference, "comment", RebuildReferenceComment), nil)
+	references.insertElement(reference, nil)
+}
@@ -0,0 +1,91 @@
+package sbom
+
+import (
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/model"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/service"
+)
+
+var testResults = map[string]service.VersionStatus{
+	"pkg:maven/com.example/foo-core@1.0?type=jar": {
+		Result: model.LookupResult{
+			ArtifactID:           "foo-core",
+			Status:               model.StatusPartiallyReproducible,
+			Version:              "1.0",
+			RebuildProjectUrl:    "https://example.com/foo/README.md",
+			FileStats:            &model.FileStats{ModuleReproducibleFiles: 1, ModuleNonReproducibleFiles: 1},
+			NonReproducibleFiles: []string{"foo-core-1.0.jar"},
+		},
+		Details: &model.VersionDetails{Version: &model.Version{Files: map[string]model.File{
+			"foo-core-1.0.jar": {Checksum: "abcdef", Reproducible: false},
+			"foo-core-1.0.pom": {Checksum: "123456", Reproducible: true},
+		}}},
+	},
+	"pkg:maven/com.example/bar@2.0":  {Result: model.LookupResult{Status: model.StatusUnknown}},
+	"pkg:maven/com.example/demo@3.0": {Result: model.LookupResult{Status: model.StatusReproducible, Version: "3.0", FileStats: &model.FileStats{ModuleReproducibleFiles: 2}}},
+}
+
+func TestEnrichCycloneDXJSON(t *testing.T) {
+	content, err := os.ReadFile("testdata/cyclonedx.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bom, err := ParseCycloneDX(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"pkg:maven/com.example/bar@2.0", "pkg:maven/com.example/foo-core@1.0?type=jar"}, bom.Purls()); diff != "" {
+		t.Errorf("unexpected purls (-want +got):\n%s", diff)
+	}
+	if count := bom.Enrich(testResults); count != 2 {
+		t.Errorf("expected 2 enriched components, got %d", count)
+	}
+
+	// enriching twice must not duplicate properties or references
+	bom.Enrich(testResults)
+	output, err := bom.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := os.ReadFile("testdata/cyclonedx.enriched.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(string(expected), string(output)); diff != "" {
+		t.Errorf("unexpected output (-want +got):\n%s", diff)
+	}
+}
+
+func TestEnrichCycloneDXXML(t *testing.T) {
+	content, err := os.ReadFile("testdata/cyclonedx.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bom, err := ParseCycloneDX(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"pkg:maven/com.example/demo@3.0", "pkg:maven/com.example/foo-core@1.0?type=jar", "pkg:maven/com.example/bar@2.0"}, bom.Purls()); diff != "" {
+		t.Errorf("unexpected purls (-want +got):\n%s", diff)
+	}
+	if count := bom.Enrich(testResults); count != 3 {
+		t.Errorf("expected 3 enriched components, got %d", count)
+	}
+
+	// enriching twice must not duplicate properties or references
+	bom.Enrich(testResults)
+	output, err := bom.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := os.ReadFile("testdata/cyclonedx.enriched.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(string(expected), string(output)); diff != "" {
+		t.Errorf("unexpected output (-want +got):\n%s", diff)
+	}
+}
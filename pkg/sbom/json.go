@@ -0,0 +1,123 @@
+package sbom
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+)
+
+// jsonObject is a json object that keeps the order of its fields, new fields are appended
+//
+// Documents are decoded into a generic tree of *jsonObject, []any, string, json.Number, bool and nil values.
+type jsonObject struct {
+	keys   []string
+	values map[string]any
+}
+
+func newJSONObject() *jsonObject {
+	return &jsonObject{values: make(map[string]any)}
+}
+
+// get returns the value of a field, nil if the field or the object does not exist
+func (o *jsonObject) get(key string) any {
+	if o == nil {
+		return nil
+	}
+	return o.values[key]
+}
+
+// set replaces the value of a field, new fields are added at the end
+func (o *jsonObject) set(key string, value any) {
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = value
+}
+
+// MarshalJSON encodes the fields in their original order
+func (o *jsonObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+
+	buf.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := encoder.Encode(key); err != nil {
+			return nil, err
+		}
+		buf.WriteByte(':')
+		if err := encoder.Encode(o.values[key]); err != nil {
+			return nil, err
+		}
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// parseJSONObject decodes a json document with an object as root element
+func parseJSONObject(content []byte) (*jsonObject, error) {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber() // keep numbers as is
+
+	value, err := decodeJSONValue(decoder)
+	if err != nil {
+		return nil, err
+	}
+	object, ok := value.(*jsonObject)
+	if !ok {
+		return nil, errors.New("root element is not an object")
+	}
+	return object, nil
+}
+
+func decodeJSONValue(decoder *json.Decoder) (any, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		object := newJSONObject()
+		for decoder.More() {
+			keyToken, keyErr := decoder.Token()
+			if keyErr != nil {
+				return nil, keyErr
+			}
+			key, _ := keyToken.(string)
+			value, valueErr := decodeJSONValue(decoder)
+			if valueErr != nil {
+				return nil, valueErr
+			}
+			object.set(key, value)
+		}
+		_, err = decoder.Token() // }
+		return object, err
+	case json.Delim('['):
+		array := make([]any, 0)
+		for decoder.More() {
+			value, valueErr := decodeJSONValue(decoder)
+			if valueErr != nil {
+				return nil, valueErr
+			}
+			array = append(array, value)
+		}
+		_, err = decoder.Token() // ]
+		return array, err
+	}
+	return token, nil
+}
+
+// asObject returns the value as object, nil if it is not an object
+func asObject(value any) *jsonObject {
+	object, _ := value.(*jsonObject)
+	return object
+}
+
+func asSlice(value any) []any {
+	slice, _ := value.([]any)
+	return slice
+}
@@ -0,0 +1,99 @@
+package sbom
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/model"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/service"
+)
+
+// Document is a sbom that can be enriched with reproducibility information
+type Document interface {
+	// Purls returns the maven package urls of all components / packages
+	Purls() []string
+	// Enrich adds the reproducibility information of the lookup results (keyed by purl), returns the number of enriched components / packages
+	Enrich(results map[string]service.VersionStatus) int
+	// Bytes returns the encoded document, in the same format as it was read
+	Bytes() ([]byte, error)
+}
+
+// Parse parses a CycloneDX (json or xml) or SPDX (json) document
+func Parse(content []byte) (Document, error) {
+	if DetectFormat(content) == FormatJSON {
+		var header struct {
+			SPDXVersion string `json:"spdxVersion"`
+		}
+		if err := json.Unmarshal(content, &header); err == nil && header.SPDXVersion != "" {
+			return ParseSPDX(content)
+		}
+	}
+	return ParseCycloneDX(content)
+}
+
+// PropertyPrefix is the namespace of all properties that are added to a sbom, existing properties with this prefix are replaced
+const PropertyPrefix = "jvm-repo-rebuild:"
+
+const (
+	PropertyStatus               = PropertyPrefix + "status"                 // reproducibility status, see model.ReproducibilityStatus
+	PropertyVersion              = PropertyPrefix + "version"                // rebuilt version
+	PropertyReproducibleFiles    = PropertyPrefix + "reproducible-files"     // number of reproducible files
+	PropertyNonReproducibleFiles = PropertyPrefix + "non-reproducible-files" // number of non-reproducible files
+	PropertyNonReproducibleFile  = PropertyPrefix + "non-reproducible-file"  // name of a non-reproducible file, repeated for each file
+)
+
+// RebuildReferenceComment is the comment of the external reference to the rebuild project
+const RebuildReferenceComment = "Reproducible Builds rebuild project"
+
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatXML  Format = "xml"
+)
+
+// DetectFormat returns the format of the document based on the first non-whitespace character
+func DetectFormat(content []byte) Format {
+	content = bytes.TrimLeft(bytes.TrimPrefix(content, []byte("\xef\xbb\xbf")), " \t\r\n")
+	if bytes.HasPrefix(content, []byte("<")) {
+		return FormatXML
+	}
+	return FormatJSON
+}
+
+type property struct {
+	Name  string
+	Value string
+}
+
+// resultProperties returns the properties describing the reproducibility of a lookup result
+func resultProperties(result model.LookupResult) []property {
+	properties := []property{{Name: PropertyStatus, Value: string(result.Status)}}
+	if result.FileStats == nil {
+		return properties
+	}
+
+	reproducible, nonReproducible := result.FileStats.Counts()
+	properties = append(properties,
+		property{Name: PropertyVersion, Value: result.Version},
+		property{Name: PropertyReproducibleFiles, Value: strconv.Itoa(reproducible)},
+		property{Name: PropertyNonReproducibleFiles, Value: strconv.Itoa(nonReproducible)},
+	)
+	for _, file := range result.NonReproducibleFiles {
+		properties = append(properties, property{Name: PropertyNonReproducibleFile, Value: file})
+	}
+	return properties
+}
+
+// encodeJSON encodes the document with indentation, without escaping html characters (e.g. & in urls)
+func encodeJSON(data any) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,232 @@
+package sbom
+
+import (
+	"errors"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/model"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/service"
+)
+
+var ErrInvalidSPDX = errors.New("invalid SPDX document")
+
+// SPDXAnnotator is the annotator of all annotations that are added to a SPDX document, existing annotations of this annotator are replaced
+const SPDXAnnotator = "Tool: jvm-repo-rebuild-index"
+
+// SPDXReferenceType is the type of the external reference to the rebuild project
+const SPDXReferenceType = "reproducible-builds"
+
+// checksum verdicts of the files of a SPDX package, the value of each property is the file name
+const (
+	PropertyChecksumMatch    = PropertyPrefix + "checksum-match"    // the checksum matches the rebuilt file
+	PropertyChecksumMismatch = PropertyPrefix + "checksum-mismatch" // the checksum differs from the rebuilt file
+	PropertyChecksumUnknown  = PropertyPrefix + "checksum-unknown"  // the file is not indexed or the document does not contain a sha512 checksum
+)
+
+// SPDX is a SPDX 2.3 document in json format
+//
+// The document is kept as generic tree, all fields that are not touched by Enrich are written back as they were read (in their original order).
+type SPDX struct {
+	// Date is used as annotation date
+	Date time.Time
+	json *jsonObject
+}
+
+// ParseSPDX parses a SPDX json document
+func ParseSPDX(content []byte) (*SPDX, error) {
+	doc := &SPDX{Date: time.Now()}
+
+	object, err := parseJSONObject(content)
+	if err != nil {
+		return nil, errors.Join(ErrInvalidSPDX, err)
+	}
+	if version, _ := object.get("spdxVersion").(string); !strings.HasPrefix(version, "SPDX-2.") {
+		return nil, errors.Join(ErrInvalidSPDX, errors.New("spdxVersion is not SPDX-2.x"))
+	}
+	doc.json = object
+	return doc, nil
+}
+
+// Bytes returns the encoded document
+func (d *SPDX) Bytes() ([]byte, error) {
+	return encodeJSON(d.json)
+}
+
+// Purls returns the maven package urls of all packages
+func (d *SPDX) Purls() []string {
+	var purls []string
+	for _, pkg := range d.packages() {
+		if purl := spdxPurl(pkg); purl != "" {
+			purls = append(purls, purl)
+		}
+	}
+	return purls
+}
+
+// Enrich adds an annotation with the reproducibility verdict and the checksum verdict of each file, and an external reference to the rebuild project to all packages with a lookup result (keyed by purl)
+//
+// Returns the number of enriched packages.
+func (d *SPDX) Enrich(results map[string]service.VersionStatus) int {
+	files := make(map[string]*jsonObject) // SPDXID -> file
+	for _, f := range asSlice(d.json.get("files")) {
+		if file := asObject(f); file != nil {
+			id, _ := file.get("SPDXID").(string)
+			files[id] = file
+		}
+	}
+
+	count := 0
+	for _, pkg := range d.packages() {
+		result, ok := results[spdxPurl(pkg)]
+		if !ok || result.Result.Error != "" {
+			continue
+		}
+
+		properties := resultProperties(result.Result)
+		if result.Details != nil {
+			properties = append(properties, spdxChecksumProperties(pkg, files, result.Result, result.Details.Files)...)
+		}
+		d.annotate(pkg, properties)
+		addSPDXReference(pkg, result.Result.RebuildProjectUrl)
+		count++
+	}
+	return count
+}
+
+func (d *SPDX) packages() []*jsonObject {
+	var packages []*jsonObject
+	for _, p := range asSlice(d.json.get("packages")) {
+		if pkg := asObject(p); pkg != nil {
+			packages = append(packages, pkg)
+		}
+	}
+	return packages
+}
+
+// annotate replaces the annotation of previous runs, the properties are written as one name=value pair per line
+func (d *SPDX) annotate(pkg *jsonObject, properties []property) {
+	lines := make([]string, 0, len(properties))
+	for _, p := range properties {
+		lines = append(lines, p.Name+"="+p.Value)
+	}
+
+	annotations := make([]any, 0)
+	for _, a := range asSlice(pkg.get("annotations")) {
+		if annotation := asObject(a); annotation != nil && annotation.get("annotator") == SPDXAnnotator {
+			continue
+		}
+		annotations = append(annotations, a)
+	}
+	annotation := newJSONObject()
+	annotation.set("annotationDate", d.Date.UTC().Format(time.RFC3339))
+	annotation.set("annotationType", "REVIEW")
+	annotation.set("annotator", SPDXAnnotator)
+	annotation.set("comment", strings.Join(lines, "\n"))
+	pkg.set("annotations", append(annotations, annotation))
+}
+
+func addSPDXReference(pkg *jsonObject, url string) {
+	if url == "" {
+		return
+	}
+
+	references := asSlice(pkg.get("externalRefs"))
+	for _, r := range references {
+		if ref := asObject(r); ref != nil && ref.get("referenceLocator") == url {
+			return
+		}
+	}
+	reference := newJSONObject()
+	reference.set("referenceCategory", "OTHER")
+	reference.set("referenceType", SPDXReferenceType)
+	reference.set("referenceLocator", url)
+	reference.set("comment", RebuildReferenceComment)
+	pkg.set("externalRefs", append(references, reference))
+}
+
+// spdxPurl returns the first maven package url of the package
+func spdxPurl(pkg *jsonObject) string {
+	for _, r := range asSlice(pkg.get("externalRefs")) {
+		ref := asObject(r)
+		if ref == nil || ref.get("referenceType") != "purl" {
+			continue
+		}
+		if locator, _ := ref.get("referenceLocator").(string); strings.HasPrefix(locator, "pkg:maven/") {
+			return locator
+		}
+	}
+	return ""
+}
+
+// spdxChecksumProperties compares the sha512 checksums of the package and its files with the indexed files
+func spdxChecksumProperties(pkg *jsonObject, files map[string]*jsonObject, result model.LookupResult, indexed map[string]model.File) []property {
+	var properties []property
+	addVerdict := func(file string, checksums any) {
+		if file == "" {
+			return
+		}
+		name := path.Base(file)
+
+		actual := spdxSHA512(checksums)
+		expected, ok := indexed[name]
+		switch {
+		case !ok || expected.Checksum == "" || actual == "":
+			properties = append(properties, property{Name: PropertyChecksumUnknown, Value: name})
+		case strings.EqualFold(expected.Checksum, actual):
+			properties = append(properties, property{Name: PropertyChecksumMatch, Value: name})
+		default:
+			properties = append(properties, property{Name: PropertyChecksumMismatch, Value: name})
+		}
+	}
+
+	// the package itself, e.g. the jar file
+	fileName, _ := pkg.get("packageFileName").(string)
+	if fileName == "" {
+		fileName = purlFileName(spdxPurl(pkg), result)
+	}
+	addVerdict(fileName, pkg.get("checksums"))
+
+	// files of the package
+	for _, id := range asSlice(pkg.get("hasFiles")) {
+		spdxId, _ := id.(string)
+		if file, ok := files[spdxId]; ok {
+			name, _ := file.get("fileName").(string)
+			addVerdict(name, file.get("checksums"))
+		}
+	}
+
+	return properties
+}
+
+// purlFileName returns the file name of the artifact in a maven repository, based on the type and classifier qualifiers of the purl
+func purlFileName(purl string, result model.LookupResult) string {
+	extension, classifier := "jar", ""
+	if _, qualifiers, found := strings.Cut(purl, "?"); found {
+		qualifiers, _, _ = strings.Cut(qualifiers, "#")
+		for _, qualifier := range strings.Split(qualifiers, "&") {
+			key, value, _ := strings.Cut(qualifier, "=")
+			switch key {
+			case "type":
+				extension = value
+			case "classifier":
+				classifier = "-" + value
+			}
+		}
+	}
+	if result.ArtifactID == "" || result.Version == "" {
+		return ""
+	}
+	return result.ArtifactID + "-" + result.Version + classifier + "." + extension
+}
+
+func spdxSHA512(checksums any) string {
+	for _, c := range asSlice(checksums) {
+		if checksum := asObject(c); checksum != nil && checksum.get("algorithm") == "SHA512" {
+			value, _ := checksum.get("checksumValue").(string)
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,46 @@
+package sbom
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestEnrichSPDX(t *testing.T) {
+	content, err := os.ReadFile("testdata/spdx.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := Parse(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spdx, ok := doc.(*SPDX)
+	if !ok {
+		t.Fatalf("expected SPDX document, got %T", doc)
+	}
+	spdx.Date = time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC)
+
+	if diff := cmp.Diff([]string{"pkg:maven/com.example/foo-core@1.0?type=jar"}, spdx.Purls()); diff != "" {
+		t.Errorf("unexpected purls (-want +got):\n%s", diff)
+	}
+	if count := spdx.Enrich(testResults); count != 1 {
+		t.Errorf("expected 1 enriched package, got %d", count)
+	}
+
+	// enriching twice must not duplicate annotations or references
+	spdx.Enrich(testResults)
+	output, err := spdx.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := os.ReadFile("testdata/spdx.enriched.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(string(expected), string(output)); diff != "" {
+		t.Errorf("unexpected output (-want +got):\n%s", diff)
+	}
+}
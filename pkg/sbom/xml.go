@@ -0,0 +1,292 @@
+package sbom
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html/charset"
+)
+
+// xmlElement is a generic xml element that keeps namespace prefixes, attributes, comments and the order of all children
+//
+// encoding/xml rewrites namespaces when marshalling, the generic tree is written by hand to keep the document as is.
+type xmlElement struct {
+	Name     xml.Name    // Space contains the namespace prefix, not the namespace url
+	Attrs    []xml.Attr  // Space contains the namespace prefix, not the namespace url
+	Children []xml.Token // *xmlElement, xml.CharData, xml.Comment or xml.ProcInst
+	created  bool        // created elements are indented when written, existing elements are written as is
+}
+
+// xmlDocument is a parsed xml document, the prolog contains everything before the root element (e.g. the xml declaration)
+type xmlDocument struct {
+	Prolog []xml.Token
+	Root   *xmlElement
+}
+
+func parseXMLDocument(content []byte) (*xmlDocument, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+	decoder.CharsetReader = charset.NewReaderLabel
+
+	doc := &xmlDocument{}
+	var stack []*xmlElement
+	for {
+		token, err := decoder.RawToken()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			element := &xmlElement{Name: t.Name, Attrs: t.Copy().Attr}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.Children = append(parent.Children, element)
+			} else if doc.Root == nil {
+				doc.Root = element
+			} else {
+				return nil, errors.New("xml document has more than one root element")
+			}
+			stack = append(stack, element)
+		case xml.EndElement:
+			if len(stack) == 0 || stack[len(stack)-1].Name != t.Name {
+				return nil, errors.New("unexpected end element " + qualifiedName(t.Name))
+			}
+			stack = stack[:len(stack)-1]
+		case xml.ProcInst:
+			if t.Target == "xml" {
+				// the document is written as utf-8
+				t = xml.ProcInst{Target: "xml", Inst: []byte(`version="1.0" encoding="UTF-8"`)}
+			}
+			doc.appendToken(stack, t.Copy())
+		default:
+			doc.appendToken(stack, xml.CopyToken(t))
+		}
+	}
+	if doc.Root == nil {
+		return nil, errors.New("xml document has no root element")
+	}
+	if len(stack) > 0 {
+		return nil, errors.New("unexpected end of xml document")
+	}
+
+	return doc, nil
+}
+
+func (d *xmlDocument) appendToken(stack []*xmlElement, token xml.Token) {
+	if len(stack) > 0 {
+		parent := stack[len(stack)-1]
+		parent.Children = append(parent.Children, token)
+	} else if d.Root == nil {
+		d.Prolog = append(d.Prolog, token)
+	}
+}
+
+var (
+	xmlTextEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	xmlAttrEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;", "\n", "&#xA;", "\r", "&#xD;", "\t", "&#x9;")
+)
+
+func (d *xmlDocument) bytes() []byte {
+	w := xmlWriter{indent: d.Root.childIndent()}
+	if i := strings.LastIndex(w.indent, "\n"); i != -1 {
+		w.indent = w.indent[i+1:]
+	}
+	for _, token := range d.Prolog {
+		w.write(token, 0)
+	}
+	w.write(d.Root, 0)
+	w.buf.WriteString("\n")
+	return w.buf.Bytes()
+}
+
+type xmlWriter struct {
+	buf    bytes.Buffer
+	indent string // indentation of a single level, detected from the root element
+}
+
+func (w *xmlWriter) write(token xml.Token, depth int) {
+	switch t := token.(type) {
+	case *xmlElement:
+		w.buf.WriteString("<" + qualifiedName(t.Name))
+		for _, attr := range t.Attrs {
+			w.buf.WriteString(" " + qualifiedName(attr.Name) + `="` + xmlAttrEscaper.Replace(attr.Value) + `"`)
+		}
+		if len(t.Children) == 0 {
+			w.buf.WriteString("/>")
+			return
+		}
+		w.buf.WriteString(">")
+		indent := t.created && w.indent != "" && t.element("") != nil
+		for _, child := range t.Children {
+			if indent {
+				w.buf.WriteString("\n" + strings.Repeat(w.indent, depth+1))
+			}
+			w.write(child, depth+1)
+		}
+		if indent {
+			w.buf.WriteString("\n" + strings.Repeat(w.indent, depth))
+		}
+		w.buf.WriteString("</" + qualifiedName(t.Name) + ">")
+	case xml.CharData:
+		w.buf.WriteString(xmlTextEscaper.Replace(string(t)))
+	case xml.Comment:
+		w.buf.WriteString("<!--" + string(t) + "-->")
+	case xml.ProcInst:
+		w.buf.WriteString("<?" + t.Target + " " + string(t.Inst) + "?>")
+	case xml.Directive:
+		w.buf.WriteString("<!" + string(t) + ">")
+	}
+}
+
+func qualifiedName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+	return name.Space + ":" + name.Local
+}
+
+// newXMLElement creates an element with the same namespace prefix as the parent element
+func newXMLElement(parent *xmlElement, local string, text string) *xmlElement {
+	element := &xmlElement{Name: xml.Name{Space: parent.Name.Space, Local: local}, created: true}
+	if text != "" {
+		element.Children = []xml.Token{xml.CharData(text)}
+	}
+	return element
+}
+
+// elements returns all child elements with the given local name, an empty name matches all elements
+func (e *xmlElement) elements(local string) []*xmlElement {
+	var result []*xmlElement
+	for _, child := range e.Children {
+		if element, ok := child.(*xmlElement); ok && (local == "" || element.Name.Local == local) {
+			result = append(result, element)
+		}
+	}
+	return result
+}
+
+// element returns the first child element with the given local name
+func (e *xmlElement) element(local string) *xmlElement {
+	if elements := e.elements(local); len(elements) > 0 {
+		return elements[0]
+	}
+	return nil
+}
+
+// text returns the trimmed text content of the element
+func (e *xmlElement) text() string {
+	var sb strings.Builder
+	for _, child := range e.Children {
+		if data, ok := child.(xml.CharData); ok {
+			sb.Write(data)
+		}
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// attr returns the value of an unprefixed attribute
+func (e *xmlElement) attr(local string) string {
+	for _, attr := range e.Attrs {
+		if attr.Name.Space == "" && attr.Name.Local == local {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// setAttr sets the value of an unprefixed attribute
+func (e *xmlElement) setAttr(local string, value string) {
+	for i, attr := range e.Attrs {
+		if attr.Name.Space == "" && attr.Name.Local == local {
+			e.Attrs[i].Value = value
+			return
+		}
+	}
+	e.Attrs = append(e.Attrs, xml.Attr{Name: xml.Name{Local: local}, Value: value})
+}
+
+// removeElements removes all child elements matching the filter, including their indentation
+func (e *xmlElement) removeElements(filter func(element *xmlElement) bool) {
+	children := e.Children[:0]
+	for _, child := range e.Children {
+		if element, ok := child.(*xmlElement); ok && filter(element) {
+			// drop the indentation of the removed element
+			if last := len(children) - 1; last >= 0 {
+				if data, isData := children[last].(xml.CharData); isData && strings.TrimSpace(string(data)) == "" {
+					children = children[:last]
+				}
+			}
+			continue
+		}
+		children = append(children, child)
+	}
+	e.Children = children
+
+	// elements that only contain whitespace are indented like created elements
+	for _, child := range e.Children {
+		if data, ok := child.(xml.CharData); !ok || strings.TrimSpace(string(data)) != "" {
+			return
+		}
+	}
+	e.Children = nil
+	e.created = true
+}
+
+// childIndent returns the whitespace in front of the first child element, empty if the children are not indented
+func (e *xmlElement) childIndent() string {
+	for i, c := range e.Children {
+		if _, ok := c.(*xmlElement); ok {
+			if data, isData := e.Children[max(i-1, 0)].(xml.CharData); i > 0 && isData && strings.TrimSpace(string(data)) == "" {
+				return string(data)
+			}
+			return ""
+		}
+	}
+	return ""
+}
+
+// insertElement inserts the child before the first child element whose local name is contained in before, otherwise the child is appended
+//
+// The indentation of the existing children is kept, created elements are indented when the document is written.
+func (e *xmlElement) insertElement(child *xmlElement, before []string) {
+	indent := e.childIndent()
+	if e.created || indent == "" {
+		indent = ""
+	}
+
+	for i, c := range e.Children {
+		if element, ok := c.(*xmlElement); ok && slices.Contains(before, element.Name.Local) {
+			if indent != "" {
+				e.Children = slices.Insert(e.Children, i, xml.Token(child), xml.Token(xml.CharData(indent)))
+			} else {
+				e.Children = slices.Insert(e.Children, i, xml.Token(child))
+			}
+			return
+		}
+	}
+
+	// append before the whitespace in front of the end element
+	if last := len(e.Children) - 1; last >= 0 && indent != "" {
+		if data, ok := e.Children[last].(xml.CharData); ok && strings.TrimSpace(string(data)) == "" {
+			e.Children = slices.Insert(e.Children, last, xml.Token(xml.CharData(indent)), xml.Token(child))
+			return
+		}
+	}
+	e.Children = append(e.Children, child)
+}
+
+// getOrInsertElement returns the first child element with the given local name, the element is created if it does not exist
+func (e *xmlElement) getOrInsertElement(local string, before []string) *xmlElement {
+	if element := e.element(local); element != nil {
+		return element
+	}
+	element := newXMLElement(e, local, "")
+	e.insertElement(element, before)
+	return element
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/model"
+)
+
+// Aggregates contains the data of the files that cover the whole registry (search index, catalog and statistics)
+type Aggregates struct {
+	SearchIndex *model.SearchIndex
+	Catalog     []model.CatalogEntry
+	Stats       *model.Stats
+}
+
+// NewAggregates creates the search index, catalog entries and statistics of a registry in a single pass over its projects and artifacts
+func NewAggregates(index *RegistryIndex) *Aggregates {
+	result := &Aggregates{SearchIndex: &model.SearchIndex{Entries: []model.SearchEntry{}}}
+
+	stats := newStatsCollector()
+	for _, project := range index.Projects() {
+		result.SearchIndex.Entries = append(result.SearchIndex.Entries, model.NewSearchEntry(model.SearchTypeProject, project))
+		stats.add(project)
+	}
+	result.Stats = stats.stats()
+
+	for _, dep := range index.Dependencies() {
+		result.SearchIndex.Entries = append(result.SearchIndex.Entries, model.NewSearchEntry(model.SearchTypeMaven, dep))
+		if entry, ok := newCatalogEntry(index, dep); ok {
+			result.Catalog = append(result.Catalog, entry)
+		}
+	}
+
+	return result
+}
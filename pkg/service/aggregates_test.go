@@ -0,0 +1,28 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewAggregates(t *testing.T) {
+	index, err := LoadRegistryIndex("testdata/index/mavencentral")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	aggregates := NewAggregates(index)
+	if diff := cmp.Diff(NewSearchIndex(index), aggregates.SearchIndex); diff != "" {
+		t.Errorf("search index mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(NewCatalogEntries(index), aggregates.Catalog); diff != "" {
+		t.Errorf("catalog mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(NewStats(index), aggregates.Stats); diff != "" {
+		t.Errorf("stats mismatch (-want +got):\n%s", diff)
+	}
+	if len(aggregates.Catalog) == 0 || aggregates.Stats.Projects == 0 {
+		t.Errorf("expected catalog entries and stats, got %+v", aggregates)
+	}
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/model"
+)
+
+// DefaultBatchConcurrency is the default number of concurrent lookups for batch requests
+const DefaultBatchConcurrency = 10
+
+// VersionStatus is the reproducibility status of a coordinate, including the version details (e.g. the indexed files) if the version was found
+type VersionStatus struct {
+	Result  model.LookupResult
+	Details *model.VersionDetails
+}
+
+// LookupStatus resolves the reproducibility status of a single coordinate (maven coordinate or purl)
+func LookupStatus(s DependencyLookupService, registry string, coordinate string) model.LookupResult {
+	return LookupVersionStatus(s, registry, coordinate).Result
+}
+
+// LookupVersionStatus resolves the reproducibility status and the version details of a single coordinate (maven coordinate or purl)
+func LookupVersionStatus(s DependencyLookupService, registry string, coordinate string) VersionStatus {
+	result := model.LookupResult{
+		Coordinate: coordinate,
+		Status:     model.StatusUnknown,
+	}
+
+	gav, err := model.ParseCoordinate(coordinate)
+	if err != nil {
+		result.Error = err.Error()
+		return VersionStatus{Result: result}
+	}
+	result.GroupID = gav.GroupId
+	result.ArtifactID = gav.ArtifactId
+	result.Version = gav.Version
+	if gav.Version == "" {
+		result.Error = "version is required"
+		return VersionStatus{Result: result}
+	}
+
+	data, err := s.LookupDependencyVersionDetails(registry, gav)
+	if err != nil {
+		if errors.Is(err, ErrVersionNotFound) {
+			result.Status = model.StatusPending
+		} else if !errors.Is(err, ErrDependencyNotFound) {
+			result.Error = err.Error()
+		}
+		return VersionStatus{Result: result}
+	}
+
+	result.Version = data.ResolvedVersion
+	result.Status = data.Status()
+	result.RebuildProjectUrl = data.RebuildProjectUrl
+	result.FileStats = &data.FileStats
+	result.NonReproducibleFiles = data.NonReproducibleFiles()
+	return VersionStatus{Result: result, Details: data}
+}
+
+// LookupStatuses resolves the reproducibility status of many coordinates concurrently, the results are returned in the order of the input
+func LookupStatuses(s DependencyLookupService, registry string, coordinates []string, concurrency int) []model.LookupResult {
+	return lookupConcurrently(coordinates, concurrency, func(coordinate string) model.LookupResult {
+		return LookupStatus(s, registry, coordinate)
+	})
+}
+
+// LookupVersionStatuses resolves the reproducibility status and version details of many coordinates concurrently, the results are returned in the order of the input
+func LookupVersionStatuses(s DependencyLookupService, registry string, coordinates []string, concurrency int) []VersionStatus {
+	return lookupConcurrently(coordinates, concurrency, func(coordinate string) VersionStatus {
+		return LookupVersionStatus(s, registry, coordinate)
+	})
+}
+
+func lookupConcurrently[T any](coordinates []string, concurrency int, lookup func(coordinate string) T) []T {
+	if concurrency <= 0 {
+		concurrency = DefaultBatchConcurrency
+	}
+
+	results := make([]T, len(coordinates))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency) // semaphore to limit concurrency
+	for i, coordinate := range coordinates {
+		wg.Add(1)
+		sem <- struct{}{} // acquire semaphore
+
+		go func(i int, coordinate string) {
+			defer wg.Done()
+			defer func() {
+				<-sem // release semaphore
+			}()
+
+			results[i] = lookup(coordinate)
+		}(i, coordinate)
+	}
+	wg.Wait()
+
+	return results
+}
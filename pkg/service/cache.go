@@ -0,0 +1,389 @@
+package service
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/util"
+)
+
+// CacheKind groups cached responses by their content, each kind has its own ttl
+type CacheKind string
+
+const (
+	CacheKindIndex   CacheKind = "index"   // index.json of projects and artifacts, changes with every index update
+	CacheKindVersion CacheKind = "version" // <version>.json of artifacts
+	CacheKindPom     CacheKind = "pom"     // poms are immutable once published
+)
+
+var DefaultCacheTTL = map[CacheKind]time.Duration{
+	CacheKindIndex:   15 * time.Minute,
+	CacheKindVersion: 1 * time.Hour,
+	CacheKindPom:     24 * time.Hour,
+}
+
+const (
+	DefaultNegativeCacheTTL = 5 * time.Minute
+	DefaultMemoryCacheSize  = 10000
+	DefaultFetchTimeout     = 30 * time.Second
+	MaxResponseSize         = 64 << 20 // 64 MiB, larger responses are rejected
+)
+
+// CacheEntry is a cached http response
+type CacheEntry struct {
+	Body         []byte    `json:"body,omitempty"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	NotFound     bool      `json:"not_found,omitempty"` // negative cache entry
+	Expires      time.Time `json:"expires"`
+}
+
+// Fresh returns true if the entry can be used without revalidation
+func (e *CacheEntry) Fresh(now time.Time) bool {
+	return now.Before(e.Expires)
+}
+
+// Cache is a storage backend for cached responses, implementations must be safe for concurrent use
+type Cache interface {
+	Get(key string) (*CacheEntry, bool)
+	Set(key string, entry *CacheEntry)
+}
+
+// CacheConfig configures the caching of remote requests, a nil Cache disables caching
+type CacheConfig struct {
+	Cache       Cache
+	TTL         map[CacheKind]time.Duration // falls back to DefaultCacheTTL for missing kinds
+	NegativeTTL time.Duration               // ttl for not found responses, 0 disables negative caching
+}
+
+// memoryCache is an in-memory cache that evicts the least recently used entries
+type memoryCache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+type memoryCacheItem struct {
+	key   string
+	entry *CacheEntry
+}
+
+// NewMemoryCache creates an in-memory LRU cache holding up to size entries
+func NewMemoryCache(size int) Cache {
+	if size <= 0 {
+		size = DefaultMemoryCacheSize
+	}
+
+	return &memoryCache{
+		size:    size,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+func (c *memoryCache) Get(key string) (*CacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+
+	return element.Value.(*memoryCacheItem).entry, true
+}
+
+func (c *memoryCache) Set(key string, entry *CacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		element.Value.(*memoryCacheItem).entry = entry
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&memoryCacheItem{key: key, entry: entry})
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*memoryCacheItem).key)
+	}
+}
+
+// diskCache stores each entry as json file, named by the sha256 checksum of the key
+//
+// The number of entries is capped, the oldest written entries are evicted once the cache is full.
+type diskCache struct {
+	dir   string
+	size  int
+	mu    sync.Mutex
+	count int // number of entries on disk
+}
+
+// NewDiskCache creates a cache that persists up to size entries in the given directory, entries survive restarts
+func NewDiskCache(dir string, size int) (Cache, error) {
+	if size <= 0 {
+		size = DefaultMemoryCacheSize
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, errors.Join(errors.New("failed to create cache directory"), err)
+	}
+
+	c := &diskCache{dir: dir, size: size}
+	files, err := c.files()
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to read cache directory"), err)
+	}
+	c.count = len(files)
+	if c.count > c.size {
+		c.evict(files)
+	}
+
+	return c, nil
+}
+
+func (c *diskCache) file(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	name := hex.EncodeToString(hash[:])
+	return filepath.Join(c.dir, name[:2], name+".json")
+}
+
+func (c *diskCache) Get(key string) (*CacheEntry, bool) {
+	file := c.file(key)
+	entry, err := util.LoadFromDisk[CacheEntry](file)
+	if err != nil {
+		return nil, false
+	}
+
+	// expired negative entries have no validators, they are never used again
+	if entry.NotFound && !entry.Fresh(time.Now()) {
+		c.remove(file)
+		return nil, false
+	}
+
+	return &entry, true
+}
+
+func (c *diskCache) Set(key string, entry *CacheEntry) {
+	file := c.file(key)
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		slog.Warn("Failed to create cache directory", "dir", filepath.Dir(file), "err", err)
+		return
+	}
+	_, statErr := os.Stat(file)
+	added := errors.Is(statErr, fs.ErrNotExist)
+
+	// write to a temporary file first, concurrent readers should never see partial entries
+	tmp, err := os.CreateTemp(filepath.Dir(file), ".tmp-*")
+	if err != nil {
+		slog.Warn("Failed to write cache entry", "file", file, "err", err)
+		return
+	}
+	err = json.NewEncoder(tmp).Encode(entry)
+	tmp.Close()
+	if err == nil {
+		err = os.Rename(tmp.Name(), file)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		slog.Warn("Failed to write cache entry", "file", file, "err", err)
+		return
+	}
+
+	if added {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.count++
+		if c.count > c.size {
+			files, filesErr := c.files()
+			if filesErr != nil {
+				slog.Warn("Failed to read cache directory", "dir", c.dir, "err", filesErr)
+				return
+			}
+			c.evict(files)
+		}
+	}
+}
+
+func (c *diskCache) remove(file string) {
+	if err := os.Remove(file); err != nil {
+		return
+	}
+	c.mu.Lock()
+	c.count--
+	c.mu.Unlock()
+}
+
+type diskCacheFile struct {
+	path    string
+	modTime time.Time
+}
+
+// files returns all cache entries on disk
+func (c *diskCache) files() ([]diskCacheFile, error) {
+	var files []diskCacheFile
+	err := filepath.WalkDir(c.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".json" {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil // removed concurrently
+		}
+		files = append(files, diskCacheFile{path: path, modTime: info.ModTime()})
+		return nil
+	})
+	return files, err
+}
+
+// evict removes the oldest entries until 90% of the capacity is used, this avoids reading the cache directory on every write
+func (c *diskCache) evict(files []diskCacheFile) {
+	slices.SortFunc(files, func(a, b diskCacheFile) int {
+		return a.modTime.Compare(b.modTime)
+	})
+
+	target := c.size - c.size/10
+	for len(files) > target {
+		if err := os.Remove(files[0].path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			slog.Warn("Failed to evict cache entry", "file", files[0].path, "err", err)
+		}
+		files = files[1:]
+	}
+	c.count = len(files)
+}
+
+// cachedFetcher fetches remote files, responses are cached and revalidated using ETag / If-Modified-Since
+type cachedFetcher struct {
+	client  *http.Client
+	config  CacheConfig
+	now     func() time.Time
+	maxSize int64
+}
+
+func newCachedFetcher(config CacheConfig) *cachedFetcher {
+	return &cachedFetcher{
+		client:  &http.Client{Timeout: DefaultFetchTimeout},
+		config:  config,
+		now:     time.Now,
+		maxSize: MaxResponseSize,
+	}
+}
+
+// fetch returns the content of the url, ErrDependencyNotFound is returned for missing files
+func (f *cachedFetcher) fetch(kind CacheKind, url string) ([]byte, error) {
+	if f.config.Cache == nil {
+		entry, err := f.request(url, nil)
+		if err != nil {
+			return nil, err
+		}
+		return entry.content()
+	}
+
+	key := string(kind) + ":" + url
+	cached, ok := f.config.Cache.Get(key)
+	if ok && cached.Fresh(f.now()) {
+		return cached.content()
+	}
+
+	entry, err := f.request(url, cached)
+	if err != nil {
+		if cached != nil && !cached.NotFound {
+			slog.Warn("Failed to revalidate cached response, using stale entry", "url", url, "err", err)
+			return cached.content()
+		}
+		return nil, err
+	}
+
+	if entry.NotFound {
+		if f.config.NegativeTTL <= 0 {
+			return entry.content()
+		}
+		entry.Expires = f.now().Add(f.config.NegativeTTL)
+	} else {
+		entry.Expires = f.now().Add(f.ttl(kind))
+	}
+	f.config.Cache.Set(key, entry)
+
+	return entry.content()
+}
+
+// request sends the http request, a cached entry with validators turns it into a conditional request
+func (f *cachedFetcher) request(url string, cached *CacheEntry) (*CacheEntry, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if cached != nil && !cached.NotFound {
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		body, readErr := io.ReadAll(io.LimitReader(resp.Body, f.maxSize+1))
+		if readErr != nil {
+			return nil, readErr
+		}
+		if int64(len(body)) > f.maxSize {
+			return nil, fmt.Errorf("response exceeds the maximum size of %d bytes", f.maxSize)
+		}
+		return &CacheEntry{
+			Body:         body,
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+		}, nil
+	case http.StatusNotModified:
+		if cached == nil {
+			return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		}
+		entry := *cached
+		return &entry, nil
+	case http.StatusNotFound:
+		return &CacheEntry{NotFound: true}, nil
+	}
+
+	return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+}
+
+func (f *cachedFetcher) ttl(kind CacheKind) time.Duration {
+	if ttl, ok := f.config.TTL[kind]; ok {
+		return ttl
+	}
+	return DefaultCacheTTL[kind]
+}
+
+func (e *CacheEntry) content() ([]byte, error) {
+	if e.NotFound {
+		return nil, ErrDependencyNotFound
+	}
+	return e.Body, nil
+}
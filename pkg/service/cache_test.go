@@ -0,0 +1,153 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestCachedFetcher(t *testing.T) {
+	requests := 0
+	conditional := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path == "/missing.json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			conditional++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	fetcher := newCachedFetcher(CacheConfig{Cache: NewMemoryCache(10), NegativeTTL: time.Minute})
+	fetcher.now = func() time.Time { return now }
+
+	// fresh entries are served from the cache
+	for range 2 {
+		content, err := fetcher.fetch(CacheKindIndex, server.URL+"/index.json")
+		if err != nil || string(content) != `{}` {
+			t.Fatalf("unexpected response %q: %v", content, err)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("expected 1 request, got %d", requests)
+	}
+
+	// expired entries are revalidated
+	now = now.Add(DefaultCacheTTL[CacheKindIndex] + time.Second)
+	content, err := fetcher.fetch(CacheKindIndex, server.URL+"/index.json")
+	if err != nil || string(content) != `{}` {
+		t.Fatalf("unexpected response %q: %v", content, err)
+	}
+	if requests != 2 || conditional != 1 {
+		t.Errorf("expected a conditional request, got %d requests (%d conditional)", requests, conditional)
+	}
+
+	// not found responses are cached
+	for range 2 {
+		if _, err = fetcher.fetch(CacheKindIndex, server.URL+"/missing.json"); !errors.Is(err, ErrDependencyNotFound) {
+			t.Errorf("expected ErrDependencyNotFound, got %v", err)
+		}
+	}
+	if requests != 3 {
+		t.Errorf("expected 3 requests, got %d", requests)
+	}
+}
+
+func TestCachedFetcherMaxSize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"name":"too large"}`))
+	}))
+	defer server.Close()
+
+	fetcher := newCachedFetcher(CacheConfig{})
+	fetcher.maxSize = 8
+	if _, err := fetcher.fetch(CacheKindIndex, server.URL+"/index.json"); err == nil {
+		t.Error("expected an error for responses exceeding the maximum size")
+	}
+}
+
+func TestMemoryCacheEviction(t *testing.T) {
+	cache := NewMemoryCache(2)
+	cache.Set("a", &CacheEntry{})
+	cache.Set("b", &CacheEntry{})
+	cache.Get("a") // a is now the most recently used entry
+	cache.Set("c", &CacheEntry{})
+
+	if _, ok := cache.Get("b"); ok {
+		t.Errorf("expected b to be evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := cache.Get(key); !ok {
+			t.Errorf("expected %s to be cached", key)
+		}
+	}
+}
+
+func TestDiskCache(t *testing.T) {
+	cache, err := NewDiskCache(t.TempDir(), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cache.Set("key", &CacheEntry{Body: []byte("content"), ETag: `"v1"`})
+	entry, ok := cache.Get("key")
+	if !ok || string(entry.Body) != "content" || entry.ETag != `"v1"` {
+		t.Errorf("unexpected entry %+v", entry)
+	}
+	if _, ok = cache.Get("other"); ok {
+		t.Errorf("expected no entry")
+	}
+}
+
+func TestDiskCacheEviction(t *testing.T) {
+	cache, err := NewDiskCache(t.TempDir(), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// entries are written one minute apart, key-0 is the oldest entry
+	base := time.Now().Add(-time.Hour)
+	for i := range 10 {
+		key := fmt.Sprintf("key-%d", i)
+		cache.Set(key, &CacheEntry{Body: []byte(key)})
+		modTime := base.Add(time.Duration(i) * time.Minute)
+		if err = os.Chtimes(cache.(*diskCache).file(key), modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cache.Set("key-10", &CacheEntry{Body: []byte("key-10")})
+
+	for i := range 11 {
+		key := fmt.Sprintf("key-%d", i)
+		if _, ok := cache.Get(key); ok != (i >= 2) {
+			t.Errorf("expected %s to be cached: %t", key, i >= 2)
+		}
+	}
+}
+
+func TestDiskCacheExpiredNotFound(t *testing.T) {
+	cache, err := NewDiskCache(t.TempDir(), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cache.Set("missing", &CacheEntry{NotFound: true, Expires: time.Now().Add(-time.Minute)})
+	if _, ok := cache.Get("missing"); ok {
+		t.Errorf("expected expired not found entry to be removed")
+	}
+	if _, err = os.Stat(cache.(*diskCache).file("missing")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected cache file to be deleted, got %v", err)
+	}
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/model"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/util"
+)
+
+// CatalogDir is the directory of the catalog, relative to the registry directory of the index
+const CatalogDir = "catalog"
+
+// NewCatalogEntries creates the catalog entries of all artifacts with at least one rebuilt version
+func NewCatalogEntries(index *RegistryIndex) []model.CatalogEntry {
+	var entries []model.CatalogEntry
+	for _, dep := range index.Dependencies() {
+		if entry, ok := newCatalogEntry(index, dep); ok {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries
+}
+
+// newCatalogEntry returns the entry of the latest verified version of an artifact, false if no version is verified
+func newCatalogEntry(index *RegistryIndex, dep *model.Dependency) (model.CatalogEntry, bool) {
+	version := dep.ResolveVersion(model.VersionLatestVerified)
+	v, ok := dep.Versions[version]
+	if !ok {
+		return model.CatalogEntry{}, false
+	}
+
+	return model.CatalogEntry{
+		GroupID:      dep.GroupID,
+		ArtifactID:   dep.ArtifactID,
+		Version:      version,
+		Reproducible: v.Reproducible,
+		Project:      index.ProjectKey(model.GAV{GroupId: dep.GroupID, ArtifactId: dep.ArtifactID}),
+	}, true
+}
+
+// WriteCatalog writes the entries as gzip compressed shards (<dir>/<prefix>.tsv.gz) and the catalog index (<dir>/index.json), shards of removed prefixes are deleted
+func WriteCatalog(dir string, entries []model.CatalogEntry) (*model.Catalog, error) {
+	shards := make(map[string][]model.CatalogEntry)
+	for _, entry := range entries {
+		prefix := model.CatalogPrefix(entry.GroupID)
+		shards[prefix] = append(shards[prefix], entry)
+	}
+	prefixes := make([]string, 0, len(shards))
+	for prefix := range shards {
+		prefixes = append(prefixes, prefix)
+	}
+	slices.Sort(prefixes)
+
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, errors.Join(errors.New("failed to create catalog directory"), err)
+	}
+
+	catalog := &model.Catalog{Shards: []model.CatalogShard{}}
+	for _, prefix := range prefixes {
+		content, err := encodeCatalogShard(shards[prefix])
+		if err != nil {
+			return nil, err
+		}
+
+		file := prefix + ".tsv.gz"
+		if err = os.WriteFile(filepath.Join(dir, file), content, 0644); err != nil {
+			return nil, errors.Join(errors.New("failed to write catalog shard"), err)
+		}
+		checksum := sha256.Sum256(content)
+		catalog.Shards = append(catalog.Shards, model.CatalogShard{
+			Prefix:  prefix,
+			File:    file,
+			Entries: len(shards[prefix]),
+			SHA256:  hex.EncodeToString(checksum[:]),
+		})
+	}
+
+	// remove shards of prefixes that no longer exist
+	files, err := filepath.Glob(filepath.Join(dir, "*.tsv.gz"))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		if _, ok := shards[strings.TrimSuffix(filepath.Base(file), ".tsv.gz")]; !ok {
+			if err = os.Remove(file); err != nil {
+				return nil, errors.Join(errors.New("failed to remove catalog shard"), err)
+			}
+		}
+	}
+
+	if err = util.WriteToFile(filepath.Join(dir, "index.json"), catalog); err != nil {
+		return nil, errors.Join(errors.New("failed to write catalog index"), err)
+	}
+
+	return catalog, nil
+}
+
+// encodeCatalogShard returns the gzip compressed catalog lines, the output only depends on the entries to avoid unnecessary changes of the published files
+func encodeCatalogShard(entries []model.CatalogEntry) ([]byte, error) {
+	var buf bytes.Buffer
+	zw, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if _, err = io.WriteString(zw, entry.String()+"\n"); err != nil {
+			return nil, err
+		}
+	}
+	if err = zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// ReadCatalogShard reads the entries of a gzip compressed catalog shard
+func ReadCatalogShard(r io.Reader) ([]model.CatalogEntry, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to read catalog shard"), err)
+	}
+	defer zr.Close()
+
+	var entries []model.CatalogEntry
+	scanner := bufio.NewScanner(zr)
+	for scanner.Scan() {
+		if scanner.Text() == "" {
+			continue
+		}
+		entry, parseErr := model.ParseCatalogEntry(scanner.Text())
+		if parseErr != nil {
+			return nil, parseErr
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, scanner.Err()
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/model"
+)
+
+func TestWriteCatalog(t *testing.T) {
+	index, err := LoadRegistryIndex("testdata/index/mavencentral")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err = os.WriteFile(filepath.Join(dir, "removed.tsv.gz"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	catalog, err := WriteCatalog(dir, NewCatalogEntries(index))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(catalog.Shards) != 1 || catalog.Shards[0].Prefix != "com" || catalog.Shards[0].Entries != 1 {
+		t.Errorf("unexpected catalog %+v", catalog)
+	}
+	if _, err = os.Stat(filepath.Join(dir, "removed.tsv.gz")); !os.IsNotExist(err) {
+		t.Errorf("expected stale shard to be removed")
+	}
+
+	file, err := os.Open(filepath.Join(dir, catalog.Shards[0].File))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	entries, err := ReadCatalogShard(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []model.CatalogEntry{
+		{GroupID: "com.example", ArtifactID: "foo-core", Version: "1.0", Reproducible: true, Project: "com.example:foo"},
+	}
+	if diff := cmp.Diff(expected, entries); diff != "" {
+		t.Errorf("unexpected entries (-want +got):\n%s", diff)
+	}
+}
@@ -1,11 +1,14 @@
 package service
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
 	"maps"
+	"path/filepath"
 	"slices"
+	"strings"
 
 	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/model"
 	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/util"
@@ -22,40 +25,119 @@ var registryNames = slices.Collect(maps.Values(registryToNameMap))
 var (
 	ErrRegistryNotFound   = errors.New("registry is not supported")
 	ErrDependencyNotFound = errors.New("dependency not found")
+	ErrVersionNotFound    = errors.New("version not found")
+	// ErrLocalRegistry is returned if a file:// registry is used without enabling local registries
+	ErrLocalRegistry = errors.New("local registries are not allowed")
 )
 
 type DependencyLookupService interface {
-	// FetchPom fetches the pom file for a given coordinate from the registry
+	// FetchPom fetches the effective pom for a given coordinate from the registry (remote repository or local directory using the file:// prefix)
+	// Local directories are only supported if the service was created with WithLocalRegistries.
+	// The effective pom includes inherited values of the parent poms, interpolated properties, imported boms and managed dependency versions.
 	FetchPom(registry string, coordinate model.GAV) (*model.PomProject, error)
+	// FetchRawPom fetches the pom as published, without resolving parents, properties or imported boms
+	FetchRawPom(registry string, coordinate model.GAV) (*model.PomProject, error)
 	// CollectCoordinates is a helper function that returns all dependency coordinates for bom artifacts, otherwise it returns the input coordinate
 	CollectCoordinates(registry string, coordinate model.GAV) ([]model.GAV, error)
 	LookupProject(registry string, coordinate model.GAV) (*model.Dependency, error)
 	LookupDependency(registry string, coordinate model.GAV) (*model.Dependency, error)
 	LookupDependencyVersion(registry string, coordinate model.GAV) (*model.Version, error)
+	// LookupProjectVersionDetails returns the project version including the coordinate and rebuild project url, version aliases (e.g. latest) are resolved
+	LookupProjectVersionDetails(registry string, coordinate model.GAV) (*model.VersionDetails, error)
+	// LookupDependencyVersionDetails returns the artifact version including the coordinate and rebuild project url, version aliases (e.g. latest) are resolved
+	LookupDependencyVersionDetails(registry string, coordinate model.GAV) (*model.VersionDetails, error)
+	// LookupSearchIndex returns the search index of all projects and artifacts of the registry
+	LookupSearchIndex(registry string) (*model.SearchIndex, error)
+	// LookupProjectHistory returns the reproducibility history of all versions of a project
+	LookupProjectHistory(registry string, coordinate model.GAV) (*model.History, error)
+	// LookupFeedIndex returns the rebuild results of the registry, ordered from newest to oldest
+	LookupFeedIndex(registry string) (*model.FeedIndex, error)
+	// LookupStats returns the aggregated reproducibility statistics of the registry
+	LookupStats(registry string) (*model.Stats, error)
 }
 
 type dependencyLookupService struct {
-	LocalDir  string
-	RemoteURL string
+	LocalDir        string
+	RemoteURL       string
+	index           *ReloadableIndex
+	fetcher         *cachedFetcher
+	localRegistries bool
 }
 
-func NewDependencyLookupService(localDir, remoteURL string) DependencyLookupService {
-	return &dependencyLookupService{
+// LookupOption configures optional behaviour of the lookup service
+type LookupOption func(*dependencyLookupService)
+
+// WithLocalRegistries allows fetching poms from local maven repositories using the file:// prefix, this must only be enabled for trusted input (e.g. cli arguments)
+func WithLocalRegistries() LookupOption {
+	return func(s *dependencyLookupService) {
+		s.localRegistries = true
+	}
+}
+
+func NewDependencyLookupService(localDir, remoteURL string, opts ...LookupOption) DependencyLookupService {
+	return NewCachedDependencyLookupService(localDir, remoteURL, CacheConfig{}, opts...)
+}
+
+// NewCachedDependencyLookupService creates a lookup service that caches all remote requests (index files and poms)
+func NewCachedDependencyLookupService(localDir, remoteURL string, cache CacheConfig, opts ...LookupOption) DependencyLookupService {
+	return newDependencyLookupService(&dependencyLookupService{
 		LocalDir:  localDir,
 		RemoteURL: remoteURL,
+		fetcher:   newCachedFetcher(cache),
+	}, opts)
+}
+
+// NewInMemoryDependencyLookupService creates a lookup service that answers all index lookups from the in-memory index, poms are fetched from the registry
+func NewInMemoryDependencyLookupService(index *ReloadableIndex, cache CacheConfig, opts ...LookupOption) DependencyLookupService {
+	return newDependencyLookupService(&dependencyLookupService{
+		index:   index,
+		fetcher: newCachedFetcher(cache),
+	}, opts)
+}
+
+func newDependencyLookupService(s *dependencyLookupService, opts []LookupOption) *dependencyLookupService {
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
 func (s *dependencyLookupService) LookupProject(registry string, coordinate model.GAV) (*model.Dependency, error) {
-	return s.lookup(registry, coordinate, "project")
+	return s.lookup(registry, coordinate, indexVariantProject)
 }
 
 func (s *dependencyLookupService) LookupDependency(registry string, coordinate model.GAV) (*model.Dependency, error) {
-	return s.lookup(registry, coordinate, "maven")
+	return s.lookup(registry, coordinate, indexVariantMaven)
 }
 
 func (s *dependencyLookupService) FetchPom(registry string, coordinate model.GAV) (*model.PomProject, error) {
-	pom, err := util.LoadXMLFromURL[model.PomProject](fmt.Sprintf("https://%s/%s/%s-%s.pom", registry, coordinate.Path(false), coordinate.ArtifactId, coordinate.Version))
+	return buildEffectivePom(func(c model.GAV) (*model.PomProject, error) {
+		return s.FetchRawPom(registry, c)
+	}, coordinate)
+}
+
+func (s *dependencyLookupService) FetchRawPom(registry string, coordinate model.GAV) (*model.PomProject, error) {
+	file := fmt.Sprintf("%s/%s-%s.pom", coordinate.RepositoryPath(), coordinate.ArtifactId, coordinate.Version)
+
+	if strings.HasPrefix(strings.ToLower(registry), "file:") && !s.localRegistries {
+		return nil, errors.Join(ErrRegistryNotFound, ErrLocalRegistry)
+	}
+
+	// local maven repository, e.g. file:///home/user/.m2/repository
+	if strings.HasPrefix(registry, "file://") {
+		pom, err := util.LoadXMLFromDisk[model.PomProject](filepath.Join(strings.TrimPrefix(registry, "file://"), filepath.FromSlash(file)))
+		if err != nil {
+			return nil, errors.Join(ErrDependencyNotFound, err)
+		}
+
+		return &pom, nil
+	}
+
+	content, err := s.fetcher.fetch(CacheKindPom, fmt.Sprintf("https://%s/%s", util.TrimURLProtocolAndTrailingSlash(registry), file))
+	if err != nil {
+		return nil, errors.Join(ErrDependencyNotFound, err)
+	}
+	pom, err := util.ParseXML[model.PomProject](content)
 	if err != nil {
 		return nil, errors.Join(ErrDependencyNotFound, err)
 	}
@@ -70,14 +152,13 @@ func (s *dependencyLookupService) CollectCoordinates(registry string, coordinate
 	pom, err := s.FetchPom(registry, coordinate)
 	if err != nil {
 		slog.Error("Error fetching pom", "err", err)
+		return coordinates, nil
 	}
 	if pom.Packaging == "pom" {
 		for _, dep := range pom.DependencyManagement.Dependencies {
-			coordinates = append(coordinates, model.GAV{
-				GroupId:    dep.GroupId,
-				ArtifactId: dep.ArtifactId,
-				Version:    dep.Version,
-			})
+			if !slices.Contains(coordinates, dep.GAV()) {
+				coordinates = append(coordinates, dep.GAV())
+			}
 		}
 	}
 
@@ -90,6 +171,20 @@ func (s *dependencyLookupService) lookup(registry string, coordinate model.GAV,
 		return nil, rErr
 	}
 
+	// lookup via in-memory index
+	if s.index != nil {
+		lookupFunc := s.index.Store().Dependency
+		if variant == indexVariantProject {
+			lookupFunc = s.index.Store().Project
+		}
+		data, ok := lookupFunc(registry, coordinate)
+		if !ok {
+			return nil, ErrDependencyNotFound
+		}
+
+		return data, nil
+	}
+
 	// lookup via local filesystem
 	if s.LocalDir != "" {
 		data, err := util.LoadFromDisk[model.Dependency](fmt.Sprintf("%s/%s/%s/%s/index.json", s.LocalDir, registry, variant, coordinate.Path(true)))
@@ -102,7 +197,31 @@ func (s *dependencyLookupService) lookup(registry string, coordinate model.GAV,
 
 	// lookup via remote url
 	if s.RemoteURL != "" {
-		data, err := util.LoadFromURL[model.Dependency](fmt.Sprintf("%s/%s/%s/%s/index.json", s.RemoteURL, registry, variant, coordinate.Path(true)))
+		return fetchJSON[model.Dependency](s.fetcher, CacheKindIndex, fmt.Sprintf("%s/%s/%s/%s/index.json", s.RemoteURL, registry, variant, coordinate.Path(true)))
+	}
+
+	return nil, errors.New("no available method to lookup dependency metadata")
+}
+
+func (s *dependencyLookupService) LookupDependencyVersion(registry string, coordinate model.GAV) (*model.Version, error) {
+	registry, rErr := toRegistryName(registry)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	// lookup via in-memory index
+	if s.index != nil {
+		data, ok := s.index.Store().Dependency(registry, coordinate)
+		if !ok || data.Versions[coordinate.Version] == nil {
+			return nil, ErrDependencyNotFound
+		}
+
+		return data.Versions[coordinate.Version], nil
+	}
+
+	// lookup via local filesystem
+	if s.LocalDir != "" {
+		data, err := util.LoadFromDisk[model.Version](fmt.Sprintf("%s/%s/maven/%s.json", s.LocalDir, registry, coordinate.Path(false)))
 		if err != nil {
 			return nil, errors.Join(ErrDependencyNotFound, err)
 		}
@@ -110,18 +229,51 @@ func (s *dependencyLookupService) lookup(registry string, coordinate model.GAV,
 		return &data, nil
 	}
 
+	// lookup via remote url
+	if s.RemoteURL != "" {
+		return fetchJSON[model.Version](s.fetcher, CacheKindVersion, fmt.Sprintf("%s/%s/maven/%s.json", s.RemoteURL, registry, coordinate.Path(false)))
+	}
+
 	return nil, errors.New("no available method to lookup dependency metadata")
 }
 
-func (s *dependencyLookupService) LookupDependencyVersion(registry string, coordinate model.GAV) (*model.Version, error) {
+func (s *dependencyLookupService) LookupProjectVersionDetails(registry string, coordinate model.GAV) (*model.VersionDetails, error) {
+	data, err := s.LookupProject(registry, coordinate)
+	if err != nil {
+		return nil, err
+	}
+
+	return versionDetails(data, coordinate.Version)
+}
+
+func (s *dependencyLookupService) LookupDependencyVersionDetails(registry string, coordinate model.GAV) (*model.VersionDetails, error) {
+	data, err := s.LookupDependency(registry, coordinate)
+	if err != nil {
+		return nil, err
+	}
+
+	return versionDetails(data, coordinate.Version)
+}
+
+func (s *dependencyLookupService) LookupSearchIndex(registry string) (*model.SearchIndex, error) {
 	registry, rErr := toRegistryName(registry)
 	if rErr != nil {
 		return nil, rErr
 	}
 
+	// lookup via in-memory index
+	if s.index != nil {
+		registryIndex := s.index.Store().Registry(registry)
+		if registryIndex == nil {
+			return nil, ErrDependencyNotFound
+		}
+
+		return registryIndex.SearchIndex(), nil
+	}
+
 	// lookup via local filesystem
 	if s.LocalDir != "" {
-		data, err := util.LoadFromDisk[model.Version](fmt.Sprintf("%s/%s/maven/%s.json", s.LocalDir, registry, coordinate.Path(false)))
+		data, err := util.LoadFromDisk[model.SearchIndex](fmt.Sprintf("%s/%s/%s", s.LocalDir, registry, SearchIndexFile))
 		if err != nil {
 			return nil, errors.Join(ErrDependencyNotFound, err)
 		}
@@ -131,7 +283,92 @@ func (s *dependencyLookupService) LookupDependencyVersion(registry string, coord
 
 	// lookup via remote url
 	if s.RemoteURL != "" {
-		data, err := util.LoadFromURL[model.Version](fmt.Sprintf("%s/%s/maven/%s.json", s.RemoteURL, registry, coordinate.Path(false)))
+		return fetchJSON[model.SearchIndex](s.fetcher, CacheKindIndex, fmt.Sprintf("%s/%s/%s", s.RemoteURL, registry, SearchIndexFile))
+	}
+
+	return nil, errors.New("no available method to lookup dependency metadata")
+}
+
+func (s *dependencyLookupService) LookupProjectHistory(registry string, coordinate model.GAV) (*model.History, error) {
+	registry, rErr := toRegistryName(registry)
+	if rErr != nil {
+		return nil, rErr
+	}
+	file := filepath.ToSlash(HistoryPath(coordinate.GroupId, coordinate.ArtifactId))
+
+	// the in-memory index does not contain the history, read it from the index directory
+	localDir := s.LocalDir
+	if s.index != nil {
+		localDir = s.index.dir
+	}
+
+	// lookup via local filesystem
+	if localDir != "" {
+		data, err := util.LoadFromDisk[model.History](fmt.Sprintf("%s/%s/%s", localDir, registry, file))
+		if err != nil {
+			return nil, errors.Join(ErrDependencyNotFound, err)
+		}
+
+		return &data, nil
+	}
+
+	// lookup via remote url
+	if s.RemoteURL != "" {
+		return fetchJSON[model.History](s.fetcher, CacheKindIndex, fmt.Sprintf("%s/%s/%s", s.RemoteURL, registry, file))
+	}
+
+	return nil, errors.New("no available method to lookup dependency metadata")
+}
+
+func (s *dependencyLookupService) LookupFeedIndex(registry string) (*model.FeedIndex, error) {
+	registry, rErr := toRegistryName(registry)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	// the in-memory index does not contain the feed, read it from the index directory
+	localDir := s.LocalDir
+	if s.index != nil {
+		localDir = s.index.dir
+	}
+
+	// lookup via local filesystem
+	if localDir != "" {
+		data, err := util.LoadFromDisk[model.FeedIndex](fmt.Sprintf("%s/%s/%s", localDir, registry, FeedIndexFile))
+		if err != nil {
+			return nil, errors.Join(ErrDependencyNotFound, err)
+		}
+
+		return &data, nil
+	}
+
+	// lookup via remote url
+	if s.RemoteURL != "" {
+		return fetchJSON[model.FeedIndex](s.fetcher, CacheKindIndex, fmt.Sprintf("%s/%s/%s", s.RemoteURL, registry, FeedIndexFile))
+	}
+
+	return nil, errors.New("no available method to lookup dependency metadata")
+}
+
+func (s *dependencyLookupService) LookupStats(registry string) (*model.Stats, error) {
+	registry, rErr := toRegistryName(registry)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	// lookup via in-memory index
+	if s.index != nil {
+		registryIndex := s.index.Store().Registry(registry)
+		if registryIndex == nil {
+			return nil, ErrDependencyNotFound
+		}
+
+		return registryIndex.Stats(), nil
+	}
+
+	// lookup via local filesystem
+	if s.LocalDir != "" {
+		data, err := util.LoadFromDisk[model.Stats](fmt.Sprintf("%s/%s/%s", s.LocalDir, registry, StatsFile))
 		if err != nil {
 			return nil, errors.Join(ErrDependencyNotFound, err)
 		}
@@ -139,9 +376,45 @@ func (s *dependencyLookupService) LookupDependencyVersion(registry string, coord
 		return &data, nil
 	}
 
+	// lookup via remote url
+	if s.RemoteURL != "" {
+		return fetchJSON[model.Stats](s.fetcher, CacheKindIndex, fmt.Sprintf("%s/%s/%s", s.RemoteURL, registry, StatsFile))
+	}
+
 	return nil, errors.New("no available method to lookup dependency metadata")
 }
 
+func versionDetails(data *model.Dependency, version string) (*model.VersionDetails, error) {
+	resolvedVersion := data.ResolveVersion(version)
+	v, ok := data.Versions[resolvedVersion]
+	if !ok {
+		return nil, ErrVersionNotFound
+	}
+
+	return &model.VersionDetails{
+		GroupID:           data.GroupID,
+		ArtifactID:        data.ArtifactID,
+		ResolvedVersion:   resolvedVersion,
+		RebuildProjectUrl: data.RebuildProjectUrl,
+		Version:           v,
+	}, nil
+}
+
+// fetchJSON fetches and decodes a remote index file
+func fetchJSON[T any](fetcher *cachedFetcher, kind CacheKind, url string) (*T, error) {
+	var data T
+
+	content, err := fetcher.fetch(kind, url)
+	if err != nil {
+		return nil, errors.Join(ErrDependencyNotFound, err)
+	}
+	if err = json.Unmarshal(content, &data); err != nil {
+		return nil, errors.Join(ErrDependencyNotFound, err)
+	}
+
+	return &data, nil
+}
+
 func toRegistryName(registryName string) (string, error) {
 	if !slices.Contains(registryNames, registryName) {
 		registryName = util.TrimURLProtocolAndTrailingSlash(registryName)
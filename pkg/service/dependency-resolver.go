@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/model"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/util"
+)
+
+// ResolvedDependency is a dependency of the resolved dependency graph
+type ResolvedDependency struct {
+	model.GAV
+	Scope string // compile or runtime
+	Depth int    // 1 for direct dependencies
+}
+
+// DependencyResolver resolves the transitive dependencies of an artifact based on the poms in a maven repository (similar to the maven resolver)
+//
+// The resolver supports parent inheritance, property interpolation, dependencyManagement, bom imports, exclusions and optional dependencies.
+// Version conflicts are resolved using nearest-wins mediation, if two dependencies have the same depth the first declaration wins.
+// A resolver caches all fetched poms and is not safe for concurrent use.
+type DependencyResolver struct {
+	ctx      context.Context // resolution stops once the context is done
+	fetch    func(coordinate model.GAV) (*model.PomProject, error)
+	fetchRaw pomFetcher
+	poms     map[string]*model.PomProject
+}
+
+// NewDependencyResolver creates a resolver for the given registry, the registry can be a remote repository (e.g. repo1.maven.org/maven2) or a local directory (file:///path/to/repository)
+func NewDependencyResolver(lookupService DependencyLookupService, registry string) *DependencyResolver {
+	return &DependencyResolver{
+		ctx: context.Background(),
+		fetch: func(coordinate model.GAV) (*model.PomProject, error) {
+			return lookupService.FetchPom(registry, coordinate)
+		},
+		fetchRaw: func(coordinate model.GAV) (*model.PomProject, error) {
+			return lookupService.FetchRawPom(registry, coordinate)
+		},
+		poms: make(map[string]*model.PomProject),
+	}
+}
+
+type resolverNode struct {
+	gav        model.GAV
+	scope      string
+	depth      int
+	exclusions []model.PomExclusion
+}
+
+// Resolve returns all transitive compile and runtime dependencies of the coordinate, the coordinate itself is not included
+func (r *DependencyResolver) Resolve(coordinate model.GAV) ([]ResolvedDependency, error) {
+	root, err := r.effectivePom(coordinate)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.resolve(coordinate, root)
+}
+
+// ResolvePom returns all transitive compile and runtime dependencies of a pom that is not published (e.g. a local pom.xml), parents and dependencies are fetched from the registry
+func (r *DependencyResolver) ResolvePom(pom *model.PomProject) ([]ResolvedDependency, error) {
+	coordinate := model.GAV{GroupId: pom.GroupId, ArtifactId: pom.ArtifactId, Version: pom.Version}
+	if pom.Parent != nil {
+		coordinate.GroupId = util.Ternary(coordinate.GroupId == "", pom.Parent.GroupId, coordinate.GroupId)
+		coordinate.Version = util.Ternary(coordinate.Version == "", pom.Parent.Version, coordinate.Version)
+	}
+
+	// parents are merged as published and interpolated once with the properties of the local pom
+	root, err := buildEffectivePom(func(c model.GAV) (*model.PomProject, error) {
+		if c == coordinate {
+			return pom, nil
+		}
+		return r.fetchRaw(c)
+	}, coordinate)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.resolve(coordinate, root)
+}
+
+func (r *DependencyResolver) resolve(coordinate model.GAV, root *model.PomProject) ([]ResolvedDependency, error) {
+	var err error
+
+	// versions managed by the root pom take precedence over transitive versions
+	managedVersions := make(map[string]string)
+	for _, dep := range root.DependencyManagement.Dependencies {
+		managedVersions[dep.GroupId+":"+dep.ArtifactId] = dep.Version
+	}
+
+	var result []ResolvedDependency
+	seen := map[string]bool{coordinate.GroupId + ":" + coordinate.ArtifactId: true}
+	queue := []resolverNode{{gav: coordinate, depth: 0}}
+	for len(queue) > 0 {
+		if err = r.ctx.Err(); err != nil {
+			return nil, err
+		}
+		node := queue[0]
+		queue = queue[1:]
+
+		pom := root
+		if node.depth > 0 {
+			pom, err = r.effectivePom(node.gav)
+			if err != nil {
+				continue // dependency is still part of the result, but its dependencies are unknown
+			}
+		}
+
+		for _, dep := range pom.Dependencies {
+			scope := dep.Scope
+			if scope == "" {
+				scope = "compile"
+			}
+			if scope != "compile" && scope != "runtime" {
+				continue // test, provided, system and import are not transitive
+			}
+			if node.depth > 0 && dep.IsOptional() {
+				continue // optional dependencies are only included for the project declaring them
+			}
+			if isExcluded(node.exclusions, dep.GroupId, dep.ArtifactId) {
+				continue
+			}
+
+			// nearest wins, breadth-first traversal ensures the nearest declaration is seen first
+			key := dep.GroupId + ":" + dep.ArtifactId
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			version := dep.Version
+			if managed, ok := managedVersions[key]; ok && node.depth > 0 {
+				version = managed
+			}
+			if node.scope == "runtime" {
+				scope = "runtime" // compile dependencies of runtime dependencies are runtime dependencies
+			}
+
+			child := resolverNode{
+				gav:        model.GAV{GroupId: dep.GroupId, ArtifactId: dep.ArtifactId, Version: version},
+				scope:      scope,
+				depth:      node.depth + 1,
+				exclusions: append(append([]model.PomExclusion{}, node.exclusions...), dep.Exclusions...),
+			}
+			result = append(result, ResolvedDependency{GAV: child.gav, Scope: child.scope, Depth: child.depth})
+			queue = append(queue, child)
+		}
+	}
+
+	return result, nil
+}
+
+func (r *DependencyResolver) effectivePom(coordinate model.GAV) (*model.PomProject, error) {
+	if pom, ok := r.poms[coordinate.Coordinate()]; ok {
+		return pom, nil
+	}
+
+	pom, err := r.fetch(coordinate)
+	if err != nil {
+		return nil, err
+	}
+	r.poms[coordinate.Coordinate()] = pom
+	return pom, nil
+}
+
+func isExcluded(exclusions []model.PomExclusion, groupId string, artifactId string) bool {
+	for _, e := range exclusions {
+		if e.Matches(groupId, artifactId) {
+			return true
+		}
+	}
+	return false
+}
+
+// MaxTransitiveRoots limits the number of artifacts that are resolved for a bom, large boms (e.g. spring-boot-dependencies) manage hundreds of artifacts
+const MaxTransitiveRoots = 100
+
+// ResolveTransitiveDependencies returns the coordinates of all transitive dependencies of a coordinate, for boms the dependencies of up to MaxTransitiveRoots managed artifacts are returned
+//
+// Artifacts that can not be resolved are skipped, an error is only returned if no artifact could be resolved or the context is done.
+func ResolveTransitiveDependencies(ctx context.Context, lookupService DependencyLookupService, registry string, coordinate model.GAV) ([]model.GAV, error) {
+	// collect coordinates (includes special handling for BOMs)
+	coordinates, err := lookupService.CollectCoordinates(registry, coordinate)
+	if err != nil {
+		return nil, err
+	}
+	if len(coordinates) > MaxTransitiveRoots {
+		slog.Warn("bom manages too many artifacts, only resolving the first artifacts", "coordinate", coordinate.Coordinate(), "artifacts", len(coordinates), "limit", MaxTransitiveRoots)
+		coordinates = coordinates[:MaxTransitiveRoots]
+	}
+
+	// resolve transitive dependencies based on the poms
+	resolver := NewDependencyResolver(lookupService, registry)
+	resolver.ctx = ctx
+	var dependencies []model.GAV
+	seen := make(map[model.GAV]bool)
+	var errs []error
+	for _, c := range coordinates {
+		resolved, rErr := resolver.Resolve(c)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		if rErr != nil {
+			slog.Warn("failed to resolve dependencies", "coordinate", c.Coordinate(), "err", rErr)
+			errs = append(errs, rErr)
+			continue
+		}
+
+		for _, d := range resolved {
+			if !seen[d.GAV] {
+				seen[d.GAV] = true
+				dependencies = append(dependencies, d.GAV)
+			}
+		}
+	}
+	if len(errs) == len(coordinates) {
+		return nil, errors.Join(errs...)
+	}
+
+	return dependencies, nil
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/model"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/util"
+)
+
+func TestDependencyResolver(t *testing.T) {
+	repository, err := filepath.Abs("testdata/repository")
+	if err != nil {
+		t.Fatalf("Failed to get absolute path: %v", err)
+	}
+
+	resolver := NewDependencyResolver(NewDependencyLookupService("", "", WithLocalRegistries()), "file://"+repository)
+	got, err := resolver.Resolve(model.GAV{GroupId: "com.example", ArtifactId: "app", Version: "1.0"})
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	want := []ResolvedDependency{
+		{GAV: model.GAV{GroupId: "com.example", ArtifactId: "lib", Version: "2.0"}, Scope: "compile", Depth: 1},        // version from parent dependencyManagement
+		{GAV: model.GAV{GroupId: "com.example", ArtifactId: "util", Version: "3.0"}, Scope: "compile", Depth: 1},       // version from imported bom, wins over util:1.0 of lib
+		{GAV: model.GAV{GroupId: "com.example", ArtifactId: "opt", Version: "1.0"}, Scope: "compile", Depth: 1},        // optional, but declared by the root
+		{GAV: model.GAV{GroupId: "com.example", ArtifactId: "transitive", Version: "1.0"}, Scope: "compile", Depth: 2}, // excluded dependency is not included
+		{GAV: model.GAV{GroupId: "com.example", ArtifactId: "leaf", Version: "1.0"}, Scope: "runtime", Depth: 3},       // interpolated coordinates
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Resolve() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestDependencyResolverResolvePom(t *testing.T) {
+	repository, err := filepath.Abs("testdata/repository")
+	if err != nil {
+		t.Fatalf("Failed to get absolute path: %v", err)
+	}
+	pom, err := util.LoadXMLFromDisk[model.PomProject]("testdata/repository/com/example/app/1.0/app-1.0.pom")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pom.ArtifactId = "unpublished-app" // local pom.xml, not part of the repository
+
+	resolver := NewDependencyResolver(NewDependencyLookupService("", "", WithLocalRegistries()), "file://"+repository)
+	got, err := resolver.ResolvePom(&pom)
+	if err != nil {
+		t.Fatalf("ResolvePom() error = %v", err)
+	}
+
+	var coordinates []string
+	for _, dep := range got {
+		coordinates = append(coordinates, dep.Coordinate())
+	}
+	want := []string{"com.example:lib:2.0", "com.example:util:3.0", "com.example:opt:1.0", "com.example:transitive:1.0", "com.example:leaf:1.0"}
+	if diff := cmp.Diff(want, coordinates); diff != "" {
+		t.Errorf("ResolvePom() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestDependencyResolverResolvePomOverridesParentProperties(t *testing.T) {
+	repository, err := filepath.Abs("testdata/repository")
+	if err != nil {
+		t.Fatalf("Failed to get absolute path: %v", err)
+	}
+	pom := model.PomProject{
+		Parent:       &model.PomParent{GroupId: "com.example", ArtifactId: "parent", Version: "1.0"},
+		ArtifactId:   "unpublished-app",
+		Properties:   model.PomProperties{"lib.version": "2.1"},
+		Dependencies: []model.PomDependency{{GroupId: "com.example", ArtifactId: "lib"}},
+	}
+
+	resolver := NewDependencyResolver(NewDependencyLookupService("", "", WithLocalRegistries()), "file://"+repository)
+	got, err := resolver.ResolvePom(&pom)
+	if err != nil {
+		t.Fatalf("ResolvePom() error = %v", err)
+	}
+
+	// the managed version of the parent (${lib.version}) uses the property of the child
+	want := []ResolvedDependency{{GAV: model.GAV{GroupId: "com.example", ArtifactId: "lib", Version: "2.1"}, Scope: "compile", Depth: 1}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ResolvePom() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestFetchPomLocalRegistryNotAllowed(t *testing.T) {
+	repository, err := filepath.Abs("testdata/repository")
+	if err != nil {
+		t.Fatalf("Failed to get absolute path: %v", err)
+	}
+
+	_, err = NewDependencyLookupService("", "").FetchPom("file://"+repository, model.GAV{GroupId: "com.example", ArtifactId: "app", Version: "1.0"})
+	if !errors.Is(err, ErrLocalRegistry) || !errors.Is(err, ErrRegistryNotFound) {
+		t.Errorf("FetchPom() error = %v, want ErrLocalRegistry", err)
+	}
+}
+
+func TestCollectCoordinates(t *testing.T) {
+	repository, err := filepath.Abs("testdata/repository")
+	if err != nil {
+		t.Fatalf("Failed to get absolute path: %v", err)
+	}
+
+	// versions are defined by properties and imported boms
+	got, err := NewDependencyLookupService("", "", WithLocalRegistries()).CollectCoordinates("file://"+repository, model.GAV{GroupId: "com.example", ArtifactId: "parent", Version: "1.0"})
+	if err != nil {
+		t.Fatalf("CollectCoordinates() error = %v", err)
+	}
+
+	want := []model.GAV{
+		{GroupId: "com.example", ArtifactId: "parent", Version: "1.0"},
+		{GroupId: "com.example", ArtifactId: "lib", Version: "2.0"},
+		{GroupId: "com.example", ArtifactId: "util", Version: "3.0"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("CollectCoordinates() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestResolveTransitiveDependencies(t *testing.T) {
+	repository, err := filepath.Abs("testdata/repository")
+	if err != nil {
+		t.Fatalf("Failed to get absolute path: %v", err)
+	}
+	lookupService := NewDependencyLookupService("", "", WithLocalRegistries())
+
+	// managed artifacts without pom are skipped, dependencies are only included once
+	got, err := ResolveTransitiveDependencies(context.Background(), lookupService, "file://"+repository, model.GAV{GroupId: "com.example", ArtifactId: "platform", Version: "1.0"})
+	if err != nil {
+		t.Fatalf("ResolveTransitiveDependencies() error = %v", err)
+	}
+	want := []model.GAV{
+		{GroupId: "com.example", ArtifactId: "excluded", Version: "1.0"},
+		{GroupId: "com.example", ArtifactId: "leaf", Version: "1.0"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ResolveTransitiveDependencies() mismatch (-want +got):\n%s", diff)
+	}
+
+	// unknown artifacts
+	_, err = ResolveTransitiveDependencies(context.Background(), lookupService, "file://"+repository, model.GAV{GroupId: "com.example", ArtifactId: "missing", Version: "1.0"})
+	if !errors.Is(err, ErrDependencyNotFound) {
+		t.Errorf("expected ErrDependencyNotFound, got %v", err)
+	}
+
+	// the resolution stops once the context is done
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = ResolveTransitiveDependencies(ctx, lookupService, "file://"+repository, model.GAV{GroupId: "com.example", ArtifactId: "platform", Version: "1.0"})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
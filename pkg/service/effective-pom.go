@@ -0,0 +1,172 @@
+package service
+
+import (
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/model"
+)
+
+// maxParentDepth limits the parent hierarchy, to protect against cyclic parents
+const maxParentDepth = 20
+
+var (
+	ErrInvalidPom   = errors.New("invalid pom")
+	ErrCyclicImport = errors.New("cyclic bom import")
+)
+
+// pomFetcher returns the raw pom of a coordinate
+type pomFetcher func(coordinate model.GAV) (*model.PomProject, error)
+
+// buildEffectivePom resolves the parent hierarchy, interpolates properties, imports boms and applies the dependency management
+func buildEffectivePom(fetch pomFetcher, coordinate model.GAV) (*model.PomProject, error) {
+	return effectivePom(fetch, coordinate, nil)
+}
+
+// effectivePom builds the effective pom, imports contains the chain of boms that led to this import and is used to detect cyclic imports
+func effectivePom(fetch pomFetcher, coordinate model.GAV, imports []string) (*model.PomProject, error) {
+	if slices.Contains(imports, coordinate.Coordinate()) {
+		return nil, errors.Join(ErrInvalidPom, ErrCyclicImport, errors.New("bom "+coordinate.Coordinate()+" imports itself via "+strings.Join(imports, " -> ")))
+	}
+	if len(imports) > maxParentDepth {
+		return nil, errors.Join(ErrInvalidPom, errors.New("bom imports are nested too deep"))
+	}
+	imports = append(slices.Clone(imports), coordinate.Coordinate())
+
+	pom, err := inheritedPom(fetch, coordinate, 0)
+	if err != nil {
+		return nil, err
+	}
+	pom.Interpolate()
+
+	// declared and inherited managed dependencies take precedence over imported boms (dependencies with scope import)
+	var managed []model.PomDependency
+	for _, dep := range pom.DependencyManagement.Dependencies {
+		if dep.Scope != "import" {
+			managed = appendManaged(managed, dep)
+		}
+	}
+	for _, dep := range pom.DependencyManagement.Dependencies {
+		if dep.Scope != "import" {
+			continue
+		}
+
+		bom, bomErr := effectivePom(fetch, dep.GAV(), imports)
+		if errors.Is(bomErr, ErrCyclicImport) {
+			return nil, bomErr
+		} else if bomErr != nil {
+			slog.Warn("failed to import bom", "pom", coordinate.Coordinate(), "bom", dep.GroupId+":"+dep.ArtifactId+":"+dep.Version, "err", bomErr)
+			continue
+		}
+		for _, bomDep := range bom.DependencyManagement.Dependencies {
+			managed = appendManaged(managed, bomDep)
+		}
+	}
+	pom.DependencyManagement.Dependencies = managed
+
+	// apply dependency management to the declared dependencies
+	for i, dep := range pom.Dependencies {
+		pom.Dependencies[i] = applyManaged(dep, managed)
+	}
+
+	return pom, nil
+}
+
+// inheritedPom returns the pom merged with all of its parents, without interpolation
+func inheritedPom(fetch pomFetcher, coordinate model.GAV, depth int) (*model.PomProject, error) {
+	if depth > maxParentDepth {
+		return nil, errors.Join(ErrInvalidPom, errors.New("parent hierarchy is too deep"))
+	}
+
+	raw, err := fetch(coordinate)
+	if err != nil {
+		return nil, err
+	}
+	pom := *raw
+	pom.Dependencies = slices.Clone(raw.Dependencies)
+	pom.DependencyManagement.Dependencies = slices.Clone(raw.DependencyManagement.Dependencies)
+	pom.Properties = make(model.PomProperties)
+	if pom.Parent == nil {
+		for k, v := range raw.Properties {
+			pom.Properties[k] = v
+		}
+		return &pom, nil
+	}
+
+	// groupId and version are inherited from the parent
+	if pom.GroupId == "" {
+		pom.GroupId = pom.Parent.GroupId
+	}
+	if pom.Version == "" {
+		pom.Version = pom.Parent.Version
+	}
+
+	parent, err := inheritedPom(fetch, model.GAV{GroupId: pom.Parent.GroupId, ArtifactId: pom.Parent.ArtifactId, Version: pom.Parent.Version}, depth+1)
+	if err != nil {
+		slog.Warn("failed to fetch parent pom", "pom", coordinate.Coordinate(), "parent", pom.Parent.GroupId+":"+pom.Parent.ArtifactId+":"+pom.Parent.Version, "err", err)
+		for k, v := range raw.Properties {
+			pom.Properties[k] = v
+		}
+		return &pom, nil
+	}
+
+	// properties of the child take precedence
+	for k, v := range parent.Properties {
+		pom.Properties[k] = v
+	}
+	for k, v := range raw.Properties {
+		pom.Properties[k] = v
+	}
+
+	// dependencies and managed dependencies of the child take precedence
+	for _, dep := range parent.DependencyManagement.Dependencies {
+		pom.DependencyManagement.Dependencies = appendManaged(pom.DependencyManagement.Dependencies, dep)
+	}
+	for _, dep := range parent.Dependencies {
+		if !slices.ContainsFunc(pom.Dependencies, func(d model.PomDependency) bool { return dependencyKey(d) == dependencyKey(dep) }) {
+			pom.Dependencies = append(pom.Dependencies, dep)
+		}
+	}
+
+	return &pom, nil
+}
+
+// appendManaged adds a managed dependency, unless the dependency is already managed
+func appendManaged(managed []model.PomDependency, dep model.PomDependency) []model.PomDependency {
+	if slices.ContainsFunc(managed, func(d model.PomDependency) bool { return dependencyKey(d) == dependencyKey(dep) }) {
+		return managed
+	}
+	return append(managed, dep)
+}
+
+// applyManaged sets the version, scope and exclusions of a dependency from the dependency management
+func applyManaged(dep model.PomDependency, managed []model.PomDependency) model.PomDependency {
+	idx := slices.IndexFunc(managed, func(d model.PomDependency) bool { return dependencyKey(d) == dependencyKey(dep) })
+	if idx == -1 {
+		return dep
+	}
+
+	m := managed[idx]
+	if dep.Version == "" {
+		dep.Version = m.Version
+	}
+	if dep.Scope == "" {
+		dep.Scope = m.Scope
+	}
+	if dep.Optional == "" {
+		dep.Optional = m.Optional
+	}
+	dep.Exclusions = append(slices.Clone(dep.Exclusions), m.Exclusions...)
+	return dep
+}
+
+// dependencyKey identifies a dependency by groupId, artifactId, type and classifier
+func dependencyKey(dep model.PomDependency) string {
+	depType := dep.Type
+	if depType == "" {
+		depType = "jar"
+	}
+	return dep.GroupId + ":" + dep.ArtifactId + ":" + depType + ":" + dep.Classifier
+}
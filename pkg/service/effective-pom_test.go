@@ -0,0 +1,92 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/model"
+)
+
+// memoryFetcher returns the poms of the map, keyed by groupId:artifactId:version
+func memoryFetcher(poms map[string]*model.PomProject) pomFetcher {
+	return func(coordinate model.GAV) (*model.PomProject, error) {
+		pom, ok := poms[coordinate.Coordinate()]
+		if !ok {
+			return nil, ErrDependencyNotFound
+		}
+		return pom, nil
+	}
+}
+
+func importBom(artifactId string) model.PomDependency {
+	return model.PomDependency{GroupId: "com.example", ArtifactId: artifactId, Version: "1.0", Type: "pom", Scope: "import"}
+}
+
+func TestBuildEffectivePomCyclicImport(t *testing.T) {
+	tests := []struct {
+		name string
+		poms map[string]*model.PomProject
+	}{
+		{
+			name: "self import",
+			poms: map[string]*model.PomProject{
+				"com.example:bom:1.0": {GroupId: "com.example", ArtifactId: "bom", Version: "1.0", DependencyManagement: model.PomDependencyManagement{Dependencies: []model.PomDependency{importBom("bom")}}},
+			},
+		},
+		{
+			name: "mutual import",
+			poms: map[string]*model.PomProject{
+				"com.example:bom:1.0":   {GroupId: "com.example", ArtifactId: "bom", Version: "1.0", DependencyManagement: model.PomDependencyManagement{Dependencies: []model.PomDependency{importBom("other")}}},
+				"com.example:other:1.0": {GroupId: "com.example", ArtifactId: "other", Version: "1.0", DependencyManagement: model.PomDependencyManagement{Dependencies: []model.PomDependency{importBom("bom")}}},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := buildEffectivePom(memoryFetcher(test.poms), model.GAV{GroupId: "com.example", ArtifactId: "bom", Version: "1.0"})
+			if !errors.Is(err, ErrCyclicImport) {
+				t.Errorf("expected ErrCyclicImport, got %v", err)
+			}
+		})
+	}
+}
+
+func TestBuildEffectivePomImportPrecedence(t *testing.T) {
+	poms := map[string]*model.PomProject{
+		"com.example:app:1.0": {
+			GroupId:    "com.example",
+			ArtifactId: "app",
+			Version:    "1.0",
+			DependencyManagement: model.PomDependencyManagement{Dependencies: []model.PomDependency{
+				importBom("bom"), // declared before the explicit entry
+				{GroupId: "com.example", ArtifactId: "lib", Version: "2.0"},
+			}},
+			Dependencies: []model.PomDependency{{GroupId: "com.example", ArtifactId: "lib"}, {GroupId: "com.example", ArtifactId: "util"}},
+		},
+		"com.example:bom:1.0": {
+			GroupId:    "com.example",
+			ArtifactId: "bom",
+			Version:    "1.0",
+			DependencyManagement: model.PomDependencyManagement{Dependencies: []model.PomDependency{
+				{GroupId: "com.example", ArtifactId: "lib", Version: "1.0"},
+				{GroupId: "com.example", ArtifactId: "util", Version: "1.0"},
+			}},
+		},
+	}
+
+	pom, err := buildEffectivePom(memoryFetcher(poms), model.GAV{GroupId: "com.example", ArtifactId: "app", Version: "1.0"})
+	if err != nil {
+		t.Fatalf("buildEffectivePom() error = %v", err)
+	}
+
+	var coordinates []string
+	for _, dep := range pom.Dependencies {
+		gav := dep.GAV()
+		coordinates = append(coordinates, gav.Coordinate())
+	}
+	want := []string{"com.example:lib:2.0", "com.example:util:1.0"}
+	if diff := cmp.Diff(want, coordinates); diff != "" {
+		t.Errorf("buildEffectivePom() mismatch (-want +got):\n%s", diff)
+	}
+}
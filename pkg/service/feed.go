@@ -0,0 +1,84 @@
+package service
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/model"
+)
+
+// FeedIndexFile is the name of the feed index, stored in the registry directory of the index
+const FeedIndexFile = "feed.json"
+
+const (
+	DefaultFeedLimit = 50
+	MaxFeedLimit     = 500
+)
+
+// FeedQuery filters the feed index, empty fields match all entries
+type FeedQuery struct {
+	GroupID    string // exact groupId
+	Project    string // groupId:artifactId of the project
+	Status     model.ReproducibilityStatus
+	Event      model.HistoryEvent // first-seen or status-changed
+	Regression bool               // only status changes to a worse status
+	Limit      int
+}
+
+// NewFeedIndex creates the feed index from the project histories, the rebuild project urls are taken from the registry index
+func NewFeedIndex(histories map[string]*model.History, index *RegistryIndex) *model.FeedIndex {
+	result := &model.FeedIndex{Entries: []model.FeedEntry{}}
+	for _, key := range slices.Sorted(maps.Keys(histories)) {
+		history := histories[key]
+
+		var rebuildProjectUrl string
+		if project, ok := index.Project(model.GAV{GroupId: history.GroupID, ArtifactId: history.ArtifactID}); ok {
+			rebuildProjectUrl = project.RebuildProjectUrl
+		}
+		result.Entries = append(result.Entries, model.NewFeedEntries(history, rebuildProjectUrl)...)
+	}
+
+	slices.SortStableFunc(result.Entries, func(a, b model.FeedEntry) int {
+		return cmp.Or(
+			b.Date.Compare(a.Date),
+			strings.Compare(a.Coordinate(), b.Coordinate()),
+			model.CompareVersions(b.Version, a.Version),
+		)
+	})
+	return result
+}
+
+// FilterFeed returns the newest matching entries of the feed index
+func FilterFeed(index *model.FeedIndex, query FeedQuery) []model.FeedEntry {
+	if query.Limit <= 0 {
+		query.Limit = DefaultFeedLimit
+	}
+	query.Limit = min(query.Limit, MaxFeedLimit)
+
+	entries := []model.FeedEntry{}
+	for _, entry := range index.Entries {
+		if query.GroupID != "" && entry.GroupID != query.GroupID {
+			continue
+		}
+		if query.Project != "" && entry.Coordinate() != query.Project {
+			continue
+		}
+		if query.Status != "" && entry.Status != query.Status {
+			continue
+		}
+		if query.Event != "" && entry.Event != query.Event {
+			continue
+		}
+		if query.Regression && !entry.Regression {
+			continue
+		}
+
+		entries = append(entries, entry)
+		if len(entries) == query.Limit {
+			break
+		}
+	}
+	return entries
+}
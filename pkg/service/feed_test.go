@@ -0,0 +1,52 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/model"
+)
+
+func TestFeed(t *testing.T) {
+	current, err := LoadRegistryIndex("testdata/index/mavencentral")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reproducible := &model.Version{Files: map[string]model.File{"foo-1.0.jar": {Reproducible: true}}}
+	reproducible.SetModuleFileStats()
+	partial := &model.Version{Files: map[string]model.File{"foo-1.0.jar": {Reproducible: true}, "foo-1.0.pom": {Reproducible: false}}}
+	partial.SetModuleFileStats()
+
+	history := model.NewHistory("com.example", "foo")
+	history.Record(nil, map[string]*model.Version{"1.0": reproducible}, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	history.Record(nil, map[string]*model.Version{"1.0": partial, "1.1": reproducible}, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))
+	other := model.NewHistory("org.example", "bar")
+	other.Record(nil, map[string]*model.Version{"2.0": reproducible}, time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC))
+
+	index := NewFeedIndex(map[string]*model.History{"com.example:foo": history, "org.example:bar": other}, current)
+
+	tests := []struct {
+		name     string
+		query    FeedQuery
+		expected []string
+	}{
+		{name: "all", query: FeedQuery{}, expected: []string{"org.example:bar:2.0 first-seen", "com.example:foo:1.1 first-seen", "com.example:foo:1.0 status-changed", "com.example:foo:1.0 first-seen"}},
+		{name: "group", query: FeedQuery{GroupID: "com.example", Event: model.HistoryEventFirstSeen}, expected: []string{"com.example:foo:1.1 first-seen", "com.example:foo:1.0 first-seen"}},
+		{name: "project", query: FeedQuery{Project: "org.example:bar"}, expected: []string{"org.example:bar:2.0 first-seen"}},
+		{name: "regression", query: FeedQuery{Regression: true}, expected: []string{"com.example:foo:1.0 status-changed"}},
+		{name: "status", query: FeedQuery{Status: model.StatusReproducible, Limit: 2}, expected: []string{"org.example:bar:2.0 first-seen", "com.example:foo:1.1 first-seen"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var actual []string
+			for _, entry := range FilterFeed(index, test.query) {
+				actual = append(actual, entry.Coordinate()+":"+entry.Version+" "+string(entry.Event))
+			}
+			if diff := cmp.Diff(test.expected, actual); diff != "" {
+				t.Errorf("unexpected entries (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
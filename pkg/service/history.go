@@ -0,0 +1,81 @@
+package service
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/model"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/util"
+)
+
+// HistoryFile is the name of the reproducibility history, stored in the directory of each project
+const HistoryFile = "history.json"
+
+// HistoryPath returns the history file of a project, relative to the registry directory of the index
+func HistoryPath(groupId string, artifactId string) string {
+	gav := model.GAV{GroupId: groupId, ArtifactId: artifactId}
+	return filepath.Join(indexVariantProject, filepath.FromSlash(gav.Path(true)), HistoryFile)
+}
+
+// LoadHistories reads all history files of a registry directory (<dir>/project/...), keyed by project (groupId:artifactId)
+func LoadHistories(dir string) (map[string]*model.History, error) {
+	histories := make(map[string]*model.History)
+	if _, err := os.Stat(filepath.Join(dir, indexVariantProject)); errors.Is(err, os.ErrNotExist) {
+		return histories, nil
+	}
+
+	files, err := util.FindFiles(filepath.Join(dir, indexVariantProject), HistoryFile)
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to find history files"), err)
+	}
+	for _, file := range files {
+		if filepath.Base(file) != HistoryFile {
+			continue
+		}
+
+		history, loadErr := util.LoadFromDisk[model.History](file)
+		if loadErr != nil {
+			return nil, errors.Join(errors.New("failed to load history file "+file), loadErr)
+		}
+		histories[history.GroupID+":"+history.ArtifactID] = &history
+	}
+	return histories, nil
+}
+
+// RecordHistories records the changes between the baseline and the current index in the project histories, returns the keys of all changed histories
+//
+// Projects without history are added to the histories.
+func RecordHistories(histories map[string]*model.History, baseline *RegistryIndex, current *RegistryIndex, date time.Time) []string {
+	projects := make(map[string]*model.Dependency)
+	for _, project := range slices.Concat(baseline.Projects(), current.Projects()) {
+		projects[project.GroupID+":"+project.ArtifactID] = project
+	}
+
+	for key, project := range projects {
+		if _, ok := histories[key]; !ok {
+			histories[key] = model.NewHistory(project.GroupID, project.ArtifactID)
+		}
+	}
+
+	var changed []string
+	for key, history := range histories {
+		gav := model.GAV{GroupId: history.GroupID, ArtifactId: history.ArtifactID}
+
+		var previous, versions map[string]*model.Version
+		if project, ok := baseline.Project(gav); ok {
+			previous = project.Versions
+		}
+		if project, ok := current.Project(gav); ok {
+			versions = project.Versions
+		}
+		if history.Record(previous, versions, date) {
+			changed = append(changed, key)
+		}
+	}
+
+	slices.Sort(changed)
+	return changed
+}
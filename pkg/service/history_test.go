@@ -0,0 +1,47 @@
+package service
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/model"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/util"
+)
+
+func TestRecordHistories(t *testing.T) {
+	baseline, err := LoadRegistryIndex(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	current, err := LoadRegistryIndex("testdata/index/mavencentral")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	histories := make(map[string]*model.History)
+	changed := RecordHistories(histories, baseline, current, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	if diff := cmp.Diff([]string{"com.example:foo"}, changed); diff != "" {
+		t.Fatalf("RecordHistories() mismatch (-want +got):\n%s", diff)
+	}
+
+	// write and lookup
+	dir := t.TempDir()
+	history := histories["com.example:foo"]
+	if err = util.WriteToFile(filepath.Join(dir, "mavencentral", HistoryPath(history.GroupID, history.ArtifactID)), history); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadHistories(filepath.Join(dir, "mavencentral"))
+	if err != nil || len(loaded) != 1 {
+		t.Fatalf("LoadHistories() = %v, %v", loaded, err)
+	}
+
+	result, err := NewDependencyLookupService(dir, "").LookupProjectHistory("repo1.maven.org/maven2", model.GAV{GroupId: "com.example", ArtifactId: "foo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(history, result); diff != "" {
+		t.Errorf("LookupProjectHistory() mismatch (-want +got):\n%s", diff)
+	}
+}
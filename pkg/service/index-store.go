@@ -0,0 +1,397 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/model"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/util"
+)
+
+const (
+	indexVariantProject = "project"
+	indexVariantMaven   = "maven"
+)
+
+// CompletedFile is written by the index command after all files of a registry are generated, it triggers the reload of the index
+const CompletedFile = "completed"
+
+// reloadDelay collects file events for a short time, a deployment of a new snapshot should trigger a single reload
+const reloadDelay = 2 * time.Second
+
+// maxLoadConcurrency limits the concurrently parsed index files
+const maxLoadConcurrency = 64
+
+// IndexStore is an immutable in-memory view of a local index directory
+type IndexStore struct {
+	LoadedAt   time.Time
+	registries map[string]*RegistryIndex
+}
+
+// RegistryIndex contains the project and artifact index files of a single registry
+type RegistryIndex struct {
+	projects     map[string]*model.Dependency // groupId:artifactId -> project
+	dependencies map[string]*model.Dependency // groupId:artifactId -> artifact
+	modules      map[string]string            // artifact groupId:artifactId -> project groupId:artifactId
+
+	searchOnce  sync.Once
+	searchIndex *model.SearchIndex
+
+	statsOnce sync.Once
+	stats     *model.Stats
+}
+
+// LoadIndexStore reads all index.json files of the index directory (<dir>/<registry>/project|maven/...) into memory
+func LoadIndexStore(dir string) (*IndexStore, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to read index directory"), err)
+	}
+
+	store := &IndexStore{
+		LoadedAt:   time.Now(),
+		registries: make(map[string]*RegistryIndex),
+	}
+	for _, entry := range entries {
+		if !isDirectory(filepath.Join(dir, entry.Name()), entry) {
+			continue
+		}
+
+		registryIndex, rErr := LoadRegistryIndex(filepath.Join(dir, entry.Name()))
+		if rErr != nil {
+			return nil, rErr
+		}
+		if len(registryIndex.projects) == 0 && len(registryIndex.dependencies) == 0 {
+			continue
+		}
+		store.registries[entry.Name()] = registryIndex
+	}
+
+	return store, nil
+}
+
+// isDirectory returns true if the entry is a directory or a symlink to a directory
+func isDirectory(path string, entry os.DirEntry) bool {
+	if entry.IsDir() {
+		return true
+	}
+	if entry.Type()&os.ModeSymlink == 0 {
+		return false
+	}
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// LoadRegistryIndex reads all index.json files of a registry directory (<dir>/project|maven/...) into memory
+func LoadRegistryIndex(dir string) (*RegistryIndex, error) {
+	projects, err := loadIndexFiles(filepath.Join(dir, indexVariantProject), func(p *model.Project) string {
+		return p.GroupID + ":" + p.ArtifactID
+	})
+	if err != nil {
+		return nil, err
+	}
+	dependencies, err := loadIndexFiles(filepath.Join(dir, indexVariantMaven), func(d *model.Dependency) string {
+		return d.GroupID + ":" + d.ArtifactID
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	index := &RegistryIndex{
+		projects:     make(map[string]*model.Dependency, len(projects)),
+		dependencies: dependencies,
+		modules:      make(map[string]string),
+	}
+	for key, project := range projects {
+		index.projects[key] = &model.Dependency{
+			RebuildProjectUrl:  project.RebuildProjectUrl,
+			GroupID:            project.GroupID,
+			ArtifactID:         project.ArtifactID,
+			Versions:           project.Versions,
+			OrderedVersions:    project.OrderedVersions,
+			Latest:             project.Latest,
+			LatestRelease:      project.LatestRelease,
+			LatestReproducible: project.LatestReproducible,
+		}
+		for _, module := range project.Modules {
+			index.modules[module] = key
+		}
+	}
+	return index, nil
+}
+
+// loadIndexFiles reads all index.json files below the variant directory, the aggregated index.json in the root is skipped
+func loadIndexFiles[T any](dir string, key func(data *T) string) (map[string]*T, error) {
+	result := make(map[string]*T)
+	if _, err := os.Stat(dir); errors.Is(err, os.ErrNotExist) {
+		return result, nil
+	}
+
+	files, err := util.FindFiles(dir, "index.json")
+	if err != nil {
+		return nil, errors.Join(errors.New("failed to find index files"), err)
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var errs []error
+	sem := make(chan struct{}, maxLoadConcurrency) // semaphore to limit concurrency
+	for _, file := range files {
+		if filepath.Base(file) != "index.json" || filepath.Dir(file) == dir {
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{} // acquire semaphore
+
+		go func(file string) {
+			defer wg.Done()
+			defer func() {
+				<-sem // release semaphore
+			}()
+
+			data, loadErr := util.LoadFromDisk[T](file)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if loadErr != nil {
+				errs = append(errs, errors.Join(errors.New("failed to load index file "+file), loadErr))
+				return
+			}
+			result[key(&data)] = &data
+		}(file)
+	}
+	wg.Wait()
+
+	return result, errors.Join(errs...)
+}
+
+// Registries returns the names of all registries in the store
+func (s *IndexStore) Registries() []string {
+	var names []string
+	for name := range s.registries {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Registry returns the index of a registry, nil if the registry is not part of the store
+func (s *IndexStore) Registry(registry string) *RegistryIndex {
+	return s.registries[registry]
+}
+
+// Project returns the project index of a coordinate
+func (s *IndexStore) Project(registry string, coordinate model.GAV) (*model.Dependency, bool) {
+	r, ok := s.registries[registry]
+	if !ok {
+		return nil, false
+	}
+	return r.Project(coordinate)
+}
+
+// Dependency returns the artifact index of a coordinate
+func (s *IndexStore) Dependency(registry string, coordinate model.GAV) (*model.Dependency, bool) {
+	r, ok := s.registries[registry]
+	if !ok {
+		return nil, false
+	}
+	return r.Dependency(coordinate)
+}
+
+// Project returns the project index of a coordinate
+func (r *RegistryIndex) Project(coordinate model.GAV) (*model.Dependency, bool) {
+	data, ok := r.projects[coordinate.GroupId+":"+coordinate.ArtifactId]
+	return data, ok
+}
+
+// Dependency returns the artifact index of a coordinate
+func (r *RegistryIndex) Dependency(coordinate model.GAV) (*model.Dependency, bool) {
+	data, ok := r.dependencies[coordinate.GroupId+":"+coordinate.ArtifactId]
+	return data, ok
+}
+
+// ProjectKey returns the project (groupId:artifactId) that contains the artifact
+func (r *RegistryIndex) ProjectKey(coordinate model.GAV) string {
+	return r.modules[coordinate.GroupId+":"+coordinate.ArtifactId]
+}
+
+// Projects returns all projects, sorted by coordinate
+func (r *RegistryIndex) Projects() []*model.Dependency {
+	return sortedIndex(r.projects)
+}
+
+// Dependencies returns all artifacts, sorted by coordinate
+func (r *RegistryIndex) Dependencies() []*model.Dependency {
+	return sortedIndex(r.dependencies)
+}
+
+// SearchIndex returns the search index of the registry, it is created on first use
+func (r *RegistryIndex) SearchIndex() *model.SearchIndex {
+	r.searchOnce.Do(func() {
+		r.searchIndex = NewSearchIndex(r)
+	})
+	return r.searchIndex
+}
+
+// Stats returns the aggregated statistics of the registry, they are created on first use
+func (r *RegistryIndex) Stats() *model.Stats {
+	r.statsOnce.Do(func() {
+		r.stats = NewStats(r)
+	})
+	return r.stats
+}
+
+func sortedIndex(index map[string]*model.Dependency) []*model.Dependency {
+	keys := make([]string, 0, len(index))
+	for key := range index {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	result := make([]*model.Dependency, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, index[key])
+	}
+	return result
+}
+
+// ReloadableIndex holds the current IndexStore of a directory, reloads replace the store atomically
+type ReloadableIndex struct {
+	dir     string
+	current atomic.Pointer[IndexStore]
+	mu      sync.Mutex // serializes reloads
+}
+
+// NewReloadableIndex loads the index directory into memory
+func NewReloadableIndex(dir string) (*ReloadableIndex, error) {
+	index := &ReloadableIndex{dir: dir}
+	if err := index.Reload(); err != nil {
+		return nil, err
+	}
+
+	return index, nil
+}
+
+// Store returns the current snapshot, it is never modified and can be used without locking
+func (i *ReloadableIndex) Store() *IndexStore {
+	return i.current.Load()
+}
+
+// Reload loads the index directory and swaps the store, the previous store is kept if loading fails
+func (i *ReloadableIndex) Reload() error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	start := time.Now()
+	store, err := LoadIndexStore(i.dir)
+	if err != nil {
+		return err
+	}
+	i.current.Store(store)
+
+	slog.Info("Loaded index", "dir", i.dir, "registries", store.Registries(), "duration", time.Since(start))
+	return nil
+}
+
+// Watch reloads the index on SIGHUP and whenever the index command completed a registry or a registry is removed, until the context is cancelled
+//
+// Only the top-level directories are watched to stay below the inotify limits, the index command writes the CompletedFile of a registry last.
+func (i *ReloadableIndex) Watch(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return errors.Join(errors.New("failed to create file watcher"), err)
+	}
+	defer watcher.Close()
+
+	if err = i.watchDirectories(watcher); err != nil {
+		return err
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	defer signal.Stop(signals)
+
+	timer := time.NewTimer(reloadDelay)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-signals:
+			slog.Info("Received SIGHUP, reloading index")
+			i.reload(watcher)
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if !i.reloadOn(watcher, event) {
+				continue
+			}
+			slog.Debug("Index directory changed", "file", event.Name, "op", event.Op.String())
+			timer.Reset(reloadDelay)
+		case <-timer.C:
+			i.reload(watcher)
+		case watchErr, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			slog.Warn("Error watching index directory", "err", watchErr)
+		}
+	}
+}
+
+func (i *ReloadableIndex) reload(watcher *fsnotify.Watcher) {
+	if err := i.Reload(); err != nil {
+		slog.Error("Failed to reload index, keeping the previous index", "err", err)
+	}
+	// registries may have been added
+	if err := i.watchDirectories(watcher); err != nil {
+		slog.Warn("Failed to watch index directory", "err", err)
+	}
+}
+
+// reloadOn returns true if the event completes or removes a registry, new registry directories are watched until they are completed
+func (i *ReloadableIndex) reloadOn(watcher *fsnotify.Watcher, event fsnotify.Event) bool {
+	if filepath.Base(event.Name) == CompletedFile {
+		return event.Has(fsnotify.Create) || event.Has(fsnotify.Write)
+	}
+	if filepath.Dir(event.Name) != filepath.Clean(i.dir) {
+		return false
+	}
+
+	if event.Has(fsnotify.Create) {
+		if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+			if err = watcher.Add(event.Name); err != nil {
+				slog.Warn("Failed to watch registry directory", "dir", event.Name, "err", err)
+			}
+		}
+		return false
+	}
+	return (event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename)) && slices.Contains(i.Store().Registries(), filepath.Base(event.Name))
+}
+
+func (i *ReloadableIndex) watchDirectories(watcher *fsnotify.Watcher) error {
+	if err := watcher.Add(i.dir); err != nil {
+		return errors.Join(errors.New("failed to watch index directory"), err)
+	}
+	for _, registry := range i.Store().Registries() {
+		if err := watcher.Add(filepath.Join(i.dir, registry)); err != nil {
+			return errors.Join(errors.New("failed to watch registry directory"), err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/model"
+)
+
+func TestInMemoryLookup(t *testing.T) {
+	index, err := NewReloadableIndex("testdata/index")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"mavencentral"}, index.Store().Registries()); diff != "" {
+		t.Errorf("unexpected registries (-want +got):\n%s", diff)
+	}
+
+	s := NewInMemoryDependencyLookupService(index, CacheConfig{})
+	project, err := s.LookupProject("mavencentral", model.GAV{GroupId: "com.example", ArtifactId: "foo"})
+	if err != nil || project.ArtifactID != "foo" {
+		t.Errorf("unexpected project %+v: %v", project, err)
+	}
+	version, err := s.LookupDependencyVersion("repo1.maven.org/maven2", model.GAV{GroupId: "com.example", ArtifactId: "foo-core", Version: "1.0"})
+	if err != nil || !version.Reproducible {
+		t.Errorf("unexpected version %+v: %v", version, err)
+	}
+	if _, err = s.LookupDependency("mavencentral", model.GAV{GroupId: "com.example", ArtifactId: "foo"}); !errors.Is(err, ErrDependencyNotFound) {
+		t.Errorf("expected ErrDependencyNotFound, got %v", err)
+	}
+}
+
+func TestLoadIndexStoreSkipsFiles(t *testing.T) {
+	registryDir, err := filepath.Abs("testdata/index/mavencentral")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err = os.WriteFile(filepath.Join(dir, "README.md"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Symlink(filepath.Join(dir, "README.md"), filepath.Join(dir, "latest")); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Symlink(registryDir, filepath.Join(dir, "mavencentral")); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := LoadIndexStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"mavencentral"}, store.Registries()); diff != "" {
+		t.Errorf("unexpected registries (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/model"
+)
+
+// SearchIndexFile is the name of the search index, stored in the registry directory of the index
+const SearchIndexFile = "search.json"
+
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
+)
+
+// Search match modes
+const (
+	SearchMatchPrefix    = "prefix"
+	SearchMatchSubstring = "substring"
+)
+
+// SearchQuery filters the search index, empty fields match all entries
+type SearchQuery struct {
+	Query        string // matched against groupId, artifactId, groupId:artifactId and the project name (case-insensitive)
+	Match        string // prefix or substring (default)
+	Type         string // project or maven
+	Reproducible *bool  // reproducibility of the latest rebuilt version
+	BuildTool    string
+	Offset       int
+	Limit        int
+}
+
+type SearchResult struct {
+	Total   int                 `json:"total"`
+	Offset  int                 `json:"offset"`
+	Limit   int                 `json:"limit"`
+	Results []model.SearchEntry `json:"results"`
+}
+
+// Search returns the matching entries of the search index, exact matches are ranked before prefix and substring matches
+func Search(index *model.SearchIndex, query SearchQuery) SearchResult {
+	if query.Limit <= 0 {
+		query.Limit = DefaultSearchLimit
+	}
+	query.Limit = min(query.Limit, MaxSearchLimit)
+	query.Offset = max(query.Offset, 0)
+	term := strings.ToLower(strings.TrimSpace(query.Query))
+
+	type match struct {
+		entry model.SearchEntry
+		rank  int
+	}
+	var matches []match
+	for _, entry := range index.Entries {
+		if query.Type != "" && entry.Type != query.Type {
+			continue
+		}
+		if query.Reproducible != nil && entry.Reproducible != *query.Reproducible {
+			continue
+		}
+		if query.BuildTool != "" && !strings.EqualFold(entry.BuildTool, query.BuildTool) {
+			continue
+		}
+
+		rank, ok := searchRank(entry, term, query.Match == SearchMatchPrefix)
+		if !ok {
+			continue
+		}
+		matches = append(matches, match{entry: entry, rank: rank})
+	}
+	slices.SortStableFunc(matches, func(a, b match) int {
+		return cmp.Or(
+			cmp.Compare(a.rank, b.rank),
+			strings.Compare(a.entry.Coordinate(), b.entry.Coordinate()),
+			strings.Compare(a.entry.Type, b.entry.Type),
+		)
+	})
+
+	result := SearchResult{
+		Total:   len(matches),
+		Offset:  query.Offset,
+		Limit:   query.Limit,
+		Results: []model.SearchEntry{},
+	}
+	for i := query.Offset; i < len(matches) && i < query.Offset+query.Limit; i++ {
+		result.Results = append(result.Results, matches[i].entry)
+	}
+
+	return result
+}
+
+// searchRank returns 0 for exact matches, 1 for prefix matches and 2 for substring matches
+func searchRank(entry model.SearchEntry, term string, prefixOnly bool) (int, bool) {
+	if term == "" {
+		return 0, true
+	}
+
+	rank := -1
+	for _, value := range []string{entry.Coordinate(), entry.ArtifactID, entry.GroupID, entry.Name} {
+		value = strings.ToLower(value)
+		switch {
+		case value == "":
+			continue
+		case value == term:
+			return 0, true
+		case strings.HasPrefix(value, term):
+			rank = 1
+		case !prefixOnly && rank == -1 && strings.Contains(value, term):
+			rank = 2
+		}
+	}
+
+	return rank, rank != -1
+}
+
+// NewSearchIndex creates the search index of all projects and artifacts of a registry
+func NewSearchIndex(index *RegistryIndex) *model.SearchIndex {
+	result := &model.SearchIndex{Entries: []model.SearchEntry{}}
+	for _, project := range index.Projects() {
+		result.Entries = append(result.Entries, model.NewSearchEntry(model.SearchTypeProject, project))
+	}
+	for _, dep := range index.Dependencies() {
+		result.Entries = append(result.Entries, model.NewSearchEntry(model.SearchTypeMaven, dep))
+	}
+
+	return result
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/model"
+)
+
+func TestSearch(t *testing.T) {
+	index := &model.SearchIndex{Entries: []model.SearchEntry{
+		{Type: model.SearchTypeProject, GroupID: "io.github.xanthic.cache", ArtifactID: "cache-api", Name: "xanthic", BuildTool: "gradle", Reproducible: true},
+		{Type: model.SearchTypeMaven, GroupID: "io.github.xanthic.cache", ArtifactID: "cache-api", Name: "xanthic", BuildTool: "gradle", Reproducible: true},
+		{Type: model.SearchTypeMaven, GroupID: "io.github.xanthic.cache", ArtifactID: "cache-core", Name: "xanthic", BuildTool: "gradle", Reproducible: false},
+		{Type: model.SearchTypeMaven, GroupID: "org.apache.maven", ArtifactID: "maven-core", Name: "maven", BuildTool: "mvn", Reproducible: true},
+	}}
+	reproducible := true
+
+	tests := []struct {
+		name     string
+		query    SearchQuery
+		expected []string
+		total    int
+	}{
+		{name: "substring", query: SearchQuery{Query: "core"}, expected: []string{"maven:io.github.xanthic.cache:cache-core", "maven:org.apache.maven:maven-core"}, total: 2},
+		{name: "prefix", query: SearchQuery{Query: "core", Match: SearchMatchPrefix}, total: 0},
+		{name: "exact match first", query: SearchQuery{Query: "MAVEN-CORE"}, expected: []string{"maven:org.apache.maven:maven-core"}, total: 1},
+		{name: "project name", query: SearchQuery{Query: "xanthic", Type: model.SearchTypeProject}, expected: []string{"project:io.github.xanthic.cache:cache-api"}, total: 1},
+		{name: "filters", query: SearchQuery{Reproducible: &reproducible, BuildTool: "Gradle"}, expected: []string{"maven:io.github.xanthic.cache:cache-api", "project:io.github.xanthic.cache:cache-api"}, total: 2},
+		{name: "pagination", query: SearchQuery{Offset: 1, Limit: 2}, expected: []string{"project:io.github.xanthic.cache:cache-api", "maven:io.github.xanthic.cache:cache-core"}, total: 4},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := Search(index, test.query)
+
+			var actual []string
+			for _, entry := range result.Results {
+				actual = append(actual, entry.Type+":"+entry.Coordinate())
+			}
+			if diff := cmp.Diff(test.expected, actual); diff != "" {
+				t.Errorf("unexpected results (-want +got):\n%s", diff)
+			}
+			if result.Total != test.total {
+				t.Errorf("expected total %d, got %d", test.total, result.Total)
+			}
+		})
+	}
+}
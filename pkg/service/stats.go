@@ -0,0 +1,118 @@
+package service
+
+import (
+	"cmp"
+	"path"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/model"
+)
+
+// StatsFile is the name of the aggregated statistics, stored in the registry directory of the index
+const StatsFile = "stats.json"
+
+// maxFileTypes limits the non-reproducible file types of the statistics
+const maxFileTypes = 10
+
+// unknownStatsKey is used for versions without build tool, jdk or os
+const unknownStatsKey = "unknown"
+
+// fileClassifiers are reported as part of the file type, e.g. sources.jar instead of jar
+var fileClassifiers = []string{"sources", "javadoc", "tests", "test-fixtures", "all", "shaded"}
+
+// NewStats aggregates the statistics of all project versions of the registry index
+func NewStats(index *RegistryIndex) *model.Stats {
+	collector := newStatsCollector()
+	for _, project := range index.Projects() {
+		collector.add(project)
+	}
+	return collector.stats()
+}
+
+// statsCollector aggregates the statistics of the projects that are added
+type statsCollector struct {
+	result    *model.Stats
+	fileTypes map[string]int // non-reproducible file type -> count
+}
+
+func newStatsCollector() *statsCollector {
+	return &statsCollector{
+		result: &model.Stats{
+			BuildTools: make(map[string]*model.StatsRate),
+			JDKs:       make(map[string]*model.StatsRate),
+			OSNames:    make(map[string]*model.StatsRate),
+		},
+		fileTypes: make(map[string]int),
+	}
+}
+
+func (c *statsCollector) add(project *model.Dependency) {
+	c.result.Projects++
+	for _, version := range project.Versions {
+		c.result.Versions++
+		c.result.Files += len(version.Files)
+		c.result.Overall.Add(version)
+		statsRate(c.result.BuildTools, version.BuildTool).Add(version)
+		statsRate(c.result.JDKs, JavaMajorVersion(version.BuildJavaVersion)).Add(version)
+		statsRate(c.result.OSNames, version.BuildOSName).Add(version)
+
+		for name, file := range version.Files {
+			if !file.Reproducible {
+				c.fileTypes[FileType(name)]++
+			}
+		}
+	}
+}
+
+// stats returns the statistics of all added projects, including the most common non-reproducible file types
+func (c *statsCollector) stats() *model.Stats {
+	c.result.NonReproducibleFileTypes = []model.FileTypeCount{}
+	for fileType, count := range c.fileTypes {
+		c.result.NonReproducibleFileTypes = append(c.result.NonReproducibleFileTypes, model.FileTypeCount{Type: fileType, Count: count})
+	}
+	slices.SortFunc(c.result.NonReproducibleFileTypes, func(a, b model.FileTypeCount) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), strings.Compare(a.Type, b.Type))
+	})
+	c.result.NonReproducibleFileTypes = c.result.NonReproducibleFileTypes[:min(len(c.result.NonReproducibleFileTypes), maxFileTypes)]
+
+	return c.result
+}
+
+func statsRate(rates map[string]*model.StatsRate, key string) *model.StatsRate {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		key = unknownStatsKey
+	}
+	if _, ok := rates[key]; !ok {
+		rates[key] = &model.StatsRate{}
+	}
+	return rates[key]
+}
+
+// JavaMajorVersion returns the major version of a java version, e.g. 1.8.0_292 is 8 and "17.0.2 (Eclipse Adoptium)" is 17
+func JavaMajorVersion(version string) string {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "1.")
+	end := strings.IndexFunc(version, func(r rune) bool {
+		return !unicode.IsDigit(r)
+	})
+	if end == -1 {
+		return version
+	}
+	return version[:end]
+}
+
+// FileType returns the extension of a file including known classifiers, e.g. jar, pom or sources.jar
+func FileType(name string) string {
+	ext := path.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	fileType := strings.TrimPrefix(ext, ".")
+
+	for _, classifier := range fileClassifiers {
+		if strings.HasSuffix(base, "-"+classifier) {
+			return classifier + "." + fileType
+		}
+	}
+	return fileType
+}
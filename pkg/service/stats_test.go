@@ -0,0 +1,61 @@
+package service
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/model"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/util"
+)
+
+func TestNewStats(t *testing.T) {
+	dir := t.TempDir()
+	versions := map[string]*model.Version{
+		"1.0": {BuildTool: "mvn", BuildJavaVersion: "1.8.0_292 (Oracle)", BuildOSName: "Linux", Files: map[string]model.File{"foo-1.0.jar": {Reproducible: true}, "foo-1.0.pom": {Reproducible: true}}},
+		"1.1": {BuildTool: "mvn", BuildJavaVersion: "17.0.2 (Eclipse Adoptium)", BuildOSName: "Linux", Files: map[string]model.File{"foo-1.1.jar": {Reproducible: true}, "foo-1.1-sources.jar": {Reproducible: false}}},
+		"1.2": {BuildTool: "gradle", Files: map[string]model.File{"foo-1.2.jar": {Reproducible: false}, "foo-1.2-sources.jar": {Reproducible: false}, "foo-1.2.module": {Reproducible: true}}},
+	}
+	for _, version := range versions {
+		version.SetModuleFileStats()
+	}
+	if err := util.WriteToFile(filepath.Join(dir, "project/com/example/foo/index.json"), model.Project{GroupID: "com.example", ArtifactID: "foo", Versions: versions}); err != nil {
+		t.Fatal(err)
+	}
+	index, err := LoadRegistryIndex(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &model.Stats{
+		Projects: 1,
+		Versions: 3,
+		Files:    7,
+		Overall:  model.StatsRate{Versions: 3, ReproducibleVersions: 1, VersionRate: 33.33, Files: 7, ReproducibleFiles: 4, FileRate: 57.14},
+		BuildTools: map[string]*model.StatsRate{
+			"mvn":    {Versions: 2, ReproducibleVersions: 1, VersionRate: 50, Files: 4, ReproducibleFiles: 3, FileRate: 75},
+			"gradle": {Versions: 1, Files: 3, ReproducibleFiles: 1, FileRate: 33.33},
+		},
+		JDKs: map[string]*model.StatsRate{
+			"8":       {Versions: 1, ReproducibleVersions: 1, VersionRate: 100, Files: 2, ReproducibleFiles: 2, FileRate: 100},
+			"17":      {Versions: 1, Files: 2, ReproducibleFiles: 1, FileRate: 50},
+			"unknown": {Versions: 1, Files: 3, ReproducibleFiles: 1, FileRate: 33.33},
+		},
+		OSNames: map[string]*model.StatsRate{
+			"Linux":   {Versions: 2, ReproducibleVersions: 1, VersionRate: 50, Files: 4, ReproducibleFiles: 3, FileRate: 75},
+			"unknown": {Versions: 1, Files: 3, ReproducibleFiles: 1, FileRate: 33.33},
+		},
+		NonReproducibleFileTypes: []model.FileTypeCount{{Type: "sources.jar", Count: 2}, {Type: "jar", Count: 1}},
+	}
+	if diff := cmp.Diff(want, NewStats(index)); diff != "" {
+		t.Errorf("NewStats() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestJavaMajorVersion(t *testing.T) {
+	for version, want := range map[string]string{"1.8.0_292": "8", "17.0.2 (Eclipse Adoptium)": "17", "11": "11", "21-ea": "21", "": ""} {
+		if got := JavaMajorVersion(version); got != want {
+			t.Errorf("JavaMajorVersion(%q) = %q, want %q", version, got, want)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package site
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/model"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/service"
+)
+
+//go:embed templates
+var templates embed.FS
+
+var funcs = template.FuncMap{
+	"status": func(status model.ReproducibilityStatus) template.HTML {
+		escaped := template.HTMLEscapeString(string(status))
+		return template.HTML(`<span class="status ` + escaped + `">` + escaped + `</span>`)
+	},
+	"percent": func(value int, total int) string {
+		if total == 0 {
+			return "0%"
+		}
+		return fmt.Sprintf("%.1f%%", float64(value)*100/float64(total))
+	},
+}
+
+var (
+	groupsTemplate  = parseTemplate("groups.html")
+	groupTemplate   = parseTemplate("group.html")
+	projectTemplate = parseTemplate("project.html")
+	versionTemplate = parseTemplate("version.html")
+)
+
+func parseTemplate(name string) *template.Template {
+	return template.Must(template.New(name).Funcs(funcs).ParseFS(templates, "templates/layout.html", "templates/"+name))
+}
+
+// Link is an entry of the breadcrumb navigation
+type Link struct {
+	Name string
+	Href string
+}
+
+// page contains the fields used by the layout
+type page struct {
+	Title       string
+	Root        string // relative path to the site root, e.g. ../../
+	Breadcrumbs []Link
+}
+
+type groupsPage struct {
+	page
+	Groups   []groupSummary
+	Projects int
+}
+
+type groupSummary struct {
+	GroupID      string
+	Projects     int
+	Versions     int
+	Reproducible int // versions with status reproducible
+}
+
+type groupPage struct {
+	page
+	Projects []model.SearchEntry
+}
+
+type projectPage struct {
+	page
+	Project  *model.Dependency
+	Versions []versionRow
+}
+
+type versionRow struct {
+	Name         string
+	Version      *model.Version
+	Reproducible int
+	Total        int
+}
+
+type versionPage struct {
+	page
+	RebuildProjectUrl string
+	Version           *model.Version
+	Files             []fileRow
+}
+
+type fileRow struct {
+	Name string
+	File model.File
+}
+
+// Generate renders the html pages of all projects of the registry index into the output directory, returns the number of written pages
+//
+// The site contains a group listing (index.html), a page per group (<groupId>/index.html), a page per project (<groupId>/<artifactId>/index.html)
+// and a page per version (<groupId>/<artifactId>/<version>.html), all links are relative.
+func Generate(outputDir string, index *service.RegistryIndex) (int, error) {
+	groups := make(map[string][]*model.Dependency)
+	for _, project := range index.Projects() {
+		groups[project.GroupID] = append(groups[project.GroupID], project)
+	}
+
+	if err := os.MkdirAll(outputDir, os.ModePerm); err != nil {
+		return 0, errors.Join(errors.New("failed to create site directory"), err)
+	}
+	style, err := templates.ReadFile("templates/style.css")
+	if err != nil {
+		return 0, err
+	}
+	if err = os.WriteFile(filepath.Join(outputDir, "style.css"), style, 0644); err != nil {
+		return 0, errors.Join(errors.New("failed to write stylesheet"), err)
+	}
+
+	count := 0
+	write := func(file string, tmpl *template.Template, data any) error {
+		if writeErr := writePage(filepath.Join(outputDir, file), tmpl, data); writeErr != nil {
+			return writeErr
+		}
+		count++
+		return nil
+	}
+
+	overview := groupsPage{page: page{Title: "Groups"}, Projects: len(index.Projects())}
+	for _, groupId := range slices.Sorted(maps.Keys(groups)) {
+		summary := groupSummary{GroupID: groupId, Projects: len(groups[groupId])}
+		group := groupPage{page: page{Title: groupId, Root: "../", Breadcrumbs: []Link{{Name: groupId, Href: "index.html"}}}}
+
+		for _, project := range groups[groupId] {
+			group.Projects = append(group.Projects, model.NewSearchEntry(model.SearchTypeProject, project))
+			for _, version := range project.Versions {
+				summary.Versions++
+				if version.Status() == model.StatusReproducible {
+					summary.Reproducible++
+				}
+			}
+
+			if err = writeProject(write, project); err != nil {
+				return count, err
+			}
+		}
+
+		overview.Groups = append(overview.Groups, summary)
+		if err = write(filepath.Join(groupId, "index.html"), groupTemplate, group); err != nil {
+			return count, err
+		}
+	}
+
+	if err = write("index.html", groupsTemplate, overview); err != nil {
+		return count, err
+	}
+	return count, nil
+}
+
+// writeProject writes the project page and the page of each version
+func writeProject(write func(file string, tmpl *template.Template, data any) error, project *model.Dependency) error {
+	dir := filepath.Join(project.GroupID, project.ArtifactID)
+	coordinate := project.GroupID + ":" + project.ArtifactID
+	breadcrumbs := []Link{{Name: project.GroupID, Href: "../index.html"}, {Name: project.ArtifactID, Href: "index.html"}}
+
+	versions := slices.Collect(maps.Keys(project.Versions))
+	model.SortVersions(versions)
+	slices.Reverse(versions)
+
+	data := projectPage{page: page{Title: coordinate, Root: "../../", Breadcrumbs: breadcrumbs}, Project: project}
+	for _, name := range versions {
+		version := project.Versions[name]
+		reproducible, nonReproducible := version.FileStats.Counts()
+		data.Versions = append(data.Versions, versionRow{Name: name, Version: version, Reproducible: reproducible, Total: reproducible + nonReproducible})
+
+		versionData := versionPage{
+			page:              page{Title: coordinate + ":" + name, Root: "../../", Breadcrumbs: append(slices.Clone(breadcrumbs), Link{Name: name, Href: name + ".html"})},
+			RebuildProjectUrl: project.RebuildProjectUrl,
+			Version:           version,
+		}
+		for _, file := range slices.Sorted(maps.Keys(version.Files)) {
+			versionData.Files = append(versionData.Files, fileRow{Name: file, File: version.Files[file]})
+		}
+		if err := write(filepath.Join(dir, name+".html"), versionTemplate, versionData); err != nil {
+			return err
+		}
+	}
+
+	return write(filepath.Join(dir, "index.html"), projectTemplate, data)
+}
+
+func writePage(file string, tmpl *template.Template, data any) error {
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "layout", data); err != nil {
+		return errors.Join(errors.New("failed to render "+file), err)
+	}
+	if err := os.MkdirAll(filepath.Dir(file), os.ModePerm); err != nil {
+		return err
+	}
+	return os.WriteFile(file, buf.Bytes(), 0644)
+}
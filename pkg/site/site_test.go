@@ -0,0 +1,64 @@
+package site
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/model"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/service"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/util"
+)
+
+func TestGenerate(t *testing.T) {
+	indexDir := t.TempDir()
+	version := &model.Version{
+		BuildTool:   "gradle",
+		RebuildSpec: &model.RebuildSpec{JDK: "17"},
+		Files: map[string]model.File{
+			"foo-1.0.jar": {Checksum: "abc123", Reproducible: true},
+			"foo-1.0.pom": {Checksum: "def456", Reproducible: false, Reason: model.FileReasonDifferent, DiffoscopeUrl: "https://example.com/diffoscope"},
+		},
+	}
+	version.SetModuleFileStats()
+	project := model.Project{GroupID: "com.example", ArtifactID: "foo", RebuildProjectUrl: "https://example.com/README.md", Versions: map[string]*model.Version{"1.0": version}}
+	project.UpdateVersionOrder()
+	if err := util.WriteToFile(filepath.Join(indexDir, "project/com/example/foo/index.json"), project); err != nil {
+		t.Fatal(err)
+	}
+	index, err := service.LoadRegistryIndex(indexDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	pages, err := Generate(dir, index)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if pages != 4 {
+		t.Errorf("expected 4 pages, got %d", pages)
+	}
+
+	tests := []struct {
+		file     string
+		contains []string
+	}{
+		{file: "index.html", contains: []string{`<a href="com.example/index.html">com.example</a>`, "0 (0.0%)"}},
+		{file: "com.example/index.html", contains: []string{`<a href="foo/index.html">foo</a>`, `<link rel="stylesheet" href="../style.css">`}},
+		{file: "com.example/foo/index.html", contains: []string{`<a href="1.0.html">1.0</a>`, "1 / 2", "<td>17</td>", `href="https://example.com/README.md"`}},
+		{file: "com.example/foo/1.0.html", contains: []string{`<tr class="ok"><td>&#10003;</td><td>foo-1.0.jar</td>`, `<tr class="ko"><td>&#10007;</td><td>foo-1.0.pom</td>`, "abc123", `<a href="https://example.com/diffoscope">diffoscope</a>`}},
+	}
+	for _, test := range tests {
+		content, readErr := os.ReadFile(filepath.Join(dir, test.file))
+		if readErr != nil {
+			t.Fatal(readErr)
+		}
+		for _, expected := range test.contains {
+			if !strings.Contains(string(content), expected) {
+				t.Errorf("expected %s to contain %q", test.file, expected)
+			}
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package state
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/util"
+)
+
+// ManifestVersion is increased whenever the manifest format or the generated output changes in an incompatible way
+const ManifestVersion = 4
+
+var ErrManifestVersionMismatch = errors.New("state manifest was generated by an incompatible version")
+
+// Manifest tracks the source files that were used to generate the index and the outputs they produced
+type Manifest struct {
+	Version int              `json:"version"`
+	Builds  map[string]Build `json:"builds"`
+}
+
+// Build contains the state of a single .buildinfo / .buildcompare pair, keyed by the path of the .buildinfo file
+type Build struct {
+	Hashes  map[string]string `json:"hashes"`  // source file -> sha256 checksum
+	Project string            `json:"project"` // project key (groupId:artifactId)
+	Version string            `json:"version"`
+	Modules []string          `json:"modules,omitempty"` // maven coordinates (groupId:artifactId)
+	Outputs []string          `json:"outputs,omitempty"` // generated files, relative to the output directory
+}
+
+// Changed returns true if the source files of the build differ from the given hashes
+func (b Build) Changed(hashes map[string]string) bool {
+	if len(b.Hashes) != len(hashes) {
+		return true
+	}
+	for file, hash := range hashes {
+		if b.Hashes[file] != hash {
+			return true
+		}
+	}
+	return false
+}
+
+func NewManifest() *Manifest {
+	return &Manifest{
+		Version: ManifestVersion,
+		Builds:  make(map[string]Build),
+	}
+}
+
+// Diff compares the manifest against the current source hashes and returns the keys of all new or changed and all removed builds
+func (m *Manifest) Diff(current map[string]map[string]string) (changed []string, removed []string) {
+	for key, hashes := range current {
+		previous, ok := m.Builds[key]
+		if !ok || previous.Changed(hashes) {
+			changed = append(changed, key)
+		}
+	}
+	for key := range m.Builds {
+		if _, ok := current[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+	slices.Sort(changed)
+	slices.Sort(removed)
+
+	return changed, removed
+}
+
+// LoadManifest reads a previously written manifest from disk
+func LoadManifest(file string) (*Manifest, error) {
+	manifest, err := util.LoadFromDisk[Manifest](file)
+	if err != nil {
+		return nil, err
+	}
+	if manifest.Version != ManifestVersion {
+		return nil, ErrManifestVersionMismatch
+	}
+	if manifest.Builds == nil {
+		manifest.Builds = make(map[string]Build)
+	}
+
+	return &manifest, nil
+}
+
+// Save writes the manifest to disk
+func (m *Manifest) Save(file string) error {
+	return util.WriteToFile(file, m)
+}
+
+// HashFile returns the hex encoded sha256 checksum of a file
+func HashFile(file string) (string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err = io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
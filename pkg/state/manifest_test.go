@@ -0,0 +1,28 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestManifestDiff(t *testing.T) {
+	manifest := NewManifest()
+	manifest.Builds["a/a-1.0.buildinfo"] = Build{Hashes: map[string]string{"a/a-1.0.buildinfo": "1", "a/a-1.0.buildcompare": "2"}}
+	manifest.Builds["a/a-1.1.buildinfo"] = Build{Hashes: map[string]string{"a/a-1.1.buildinfo": "3", "a/a-1.1.buildcompare": "4"}}
+	manifest.Builds["b/b-1.0.buildinfo"] = Build{Hashes: map[string]string{"b/b-1.0.buildinfo": "5"}}
+
+	current := map[string]map[string]string{
+		"a/a-1.0.buildinfo": {"a/a-1.0.buildinfo": "1", "a/a-1.0.buildcompare": "2"},
+		"a/a-1.1.buildinfo": {"a/a-1.1.buildinfo": "3", "a/a-1.1.buildcompare": "changed"},
+		"c/c-1.0.buildinfo": {"c/c-1.0.buildinfo": "6"},
+	}
+
+	changed, removed := manifest.Diff(current)
+	if diff := cmp.Diff([]string{"a/a-1.1.buildinfo", "c/c-1.0.buildinfo"}, changed); diff != "" {
+		t.Errorf("Diff() changed mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"b/b-1.0.buildinfo"}, removed); diff != "" {
+		t.Errorf("Diff() removed mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -1,10 +1,15 @@
 package util
 
 import (
+	"bytes"
 	"encoding/json"
 	"encoding/xml"
+	"fmt"
+	"io"
 	"net/http"
 	"os"
+
+	"golang.org/x/net/html/charset"
 )
 
 func LoadFromURL[T any](url string) (T, error) {
@@ -16,6 +21,10 @@ func LoadFromURL[T any](url string) (T, error) {
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusOK {
+		return result, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
 	err = json.NewDecoder(resp.Body).Decode(&result)
 	if err != nil {
 		return result, err
@@ -33,7 +42,22 @@ func LoadXMLFromURL[T any](url string) (T, error) {
 	}
 	defer resp.Body.Close()
 
-	err = xml.NewDecoder(resp.Body).Decode(&result)
+	if resp.StatusCode != http.StatusOK {
+		return result, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return decodeXML[T](resp.Body)
+}
+
+func LoadFromDisk[T any](filename string) (T, error) {
+	var result T
+
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return result, err
+	}
+
+	err = json.Unmarshal(content, &result)
 	if err != nil {
 		return result, err
 	}
@@ -41,7 +65,7 @@ func LoadXMLFromURL[T any](url string) (T, error) {
 	return result, nil
 }
 
-func LoadFromDisk[T any](filename string) (T, error) {
+func LoadXMLFromDisk[T any](filename string) (T, error) {
 	var result T
 
 	content, err := os.ReadFile(filename)
@@ -49,7 +73,21 @@ func LoadFromDisk[T any](filename string) (T, error) {
 		return result, err
 	}
 
-	err = json.Unmarshal(content, &result)
+	return ParseXML[T](content)
+}
+
+// ParseXML decodes xml content, see decodeXML
+func ParseXML[T any](content []byte) (T, error) {
+	return decodeXML[T](bytes.NewReader(content))
+}
+
+// decodeXML decodes xml, supporting documents that are not encoded in utf-8 (e.g. ISO-8859-1 poms)
+func decodeXML[T any](r io.Reader) (T, error) {
+	var result T
+
+	decoder := xml.NewDecoder(r)
+	decoder.CharsetReader = charset.NewReaderLabel
+	err := decoder.Decode(&result)
 	if err != nil {
 		return result, err
 	}
@@ -0,0 +1,247 @@
+package verify
+
+import (
+	"archive/zip"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/model"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/service"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/util"
+)
+
+// Verdict is the result of the checksum comparison of a single artifact
+type Verdict string
+
+const (
+	VerdictMatch    Verdict = "match"    // the checksum matches the rebuilt artifact
+	VerdictMismatch Verdict = "mismatch" // the checksum differs from the rebuilt artifact
+	VerdictUnknown  Verdict = "unknown"  // the artifact is not part of the index or has no indexed checksum
+)
+
+var ErrNoCoordinate = errors.New("no maven coordinate found")
+
+// ignoredSuffixes are files in a maven repository that are not artifacts
+var ignoredSuffixes = []string{".sha1", ".sha256", ".sha512", ".md5", ".asc", ".lastUpdated", ".repositories"}
+
+// Artifact is a local artifact file
+type Artifact struct {
+	Path string    `json:"path"`
+	GAV  model.GAV `json:"gav"`
+}
+
+// Name returns the file name of the artifact, as used in the index
+func (a Artifact) Name() string {
+	return filepath.Base(a.Path)
+}
+
+// Result is the checksum verdict of a single artifact
+type Result struct {
+	Artifact
+	Verdict           Verdict `json:"verdict"`
+	Checksum          string  `json:"checksum,omitempty"`     // sha512 checksum of the local file
+	Expected          string  `json:"expected,omitempty"`     // sha512 checksum of the rebuilt file
+	Reproducible      bool    `json:"reproducible,omitempty"` // the rebuilt file is reproducible, a mismatch of a non-reproducible file is expected
+	RebuildProjectUrl string  `json:"rebuild_project_url,omitempty"`
+	Error             string  `json:"error,omitempty"`
+}
+
+// Report contains the verdict of all artifacts, in the order of the scan
+type Report struct {
+	Results    []Result `json:"results"`
+	Matches    int      `json:"matches"`
+	Mismatches int      `json:"mismatches"`
+	Unknown    int      `json:"unknown"`
+}
+
+// Scan returns all artifacts of a local maven repository (e.g. ~/.m2/repository) or a directory of jars
+//
+// The coordinate is derived from the maven repository layout (groupId/artifactId/version/file), jars outside of this layout are identified by their embedded pom.properties.
+// Unreadable directories are skipped, only an unreadable root directory is an error.
+func Scan(dir string) ([]Artifact, error) {
+	var artifacts []Artifact
+	err := filepath.WalkDir(dir, func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if file == dir {
+				return err
+			}
+
+			// unreadable directories and files are skipped, e.g. missing permissions in a shared repository
+			slog.Warn("Skipping unreadable path", "path", file, "err", err)
+			if d != nil && d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() || isIgnored(d.Name()) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, file)
+		if err != nil {
+			return err
+		}
+		if gav, ok := layoutCoordinate(filepath.ToSlash(rel)); ok {
+			artifacts = append(artifacts, Artifact{Path: file, GAV: gav})
+			return nil
+		}
+		if strings.HasSuffix(d.Name(), ".jar") {
+			gav, jarErr := JarCoordinate(file)
+			if jarErr == nil {
+				artifacts = append(artifacts, Artifact{Path: file, GAV: gav})
+			}
+		}
+		return nil
+	})
+	return artifacts, err
+}
+
+// Coordinates returns the unique coordinates of the artifacts
+func Coordinates(artifacts []Artifact) []string {
+	var coordinates []string
+	seen := make(map[string]bool)
+	for _, artifact := range artifacts {
+		if coordinate := artifact.GAV.Coordinate(); !seen[coordinate] {
+			seen[coordinate] = true
+			coordinates = append(coordinates, coordinate)
+		}
+	}
+	return coordinates
+}
+
+// Compare hashes the artifacts and compares them with the indexed checksums of the lookup results (keyed by coordinate)
+//
+// Artifacts without indexed checksum are not hashed.
+func Compare(artifacts []Artifact, statuses map[string]service.VersionStatus) Report {
+	report := Report{Results: make([]Result, 0, len(artifacts))}
+	for _, artifact := range artifacts {
+		result := Result{Artifact: artifact, Verdict: VerdictUnknown}
+
+		status := statuses[artifact.GAV.Coordinate()]
+		result.Error = status.Result.Error
+		if status.Details != nil && status.Details.Version != nil {
+			result.RebuildProjectUrl = status.Details.RebuildProjectUrl
+
+			if file, ok := status.Details.Files[artifact.Name()]; ok && file.Checksum != "" {
+				result.Expected = file.Checksum
+				result.Reproducible = file.Reproducible
+
+				checksum, err := SHA512(artifact.Path)
+				switch {
+				case err != nil:
+					result.Error = err.Error()
+				case strings.EqualFold(checksum, file.Checksum):
+					result.Checksum, result.Verdict = checksum, VerdictMatch
+				default:
+					result.Checksum, result.Verdict = checksum, VerdictMismatch
+				}
+			}
+		}
+
+		switch result.Verdict {
+		case VerdictMatch:
+			report.Matches++
+		case VerdictMismatch:
+			report.Mismatches++
+		default:
+			report.Unknown++
+		}
+		report.Results = append(report.Results, result)
+	}
+	return report
+}
+
+// SHA512 returns the hex encoded sha512 checksum of the file
+func SHA512(file string) (string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	hash := sha512.New()
+	if _, err = io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// JarCoordinate returns the coordinate of a jar based on the embedded META-INF/maven/<groupId>/<artifactId>/pom.properties
+//
+// Jars without or with multiple pom.properties (e.g. shaded jars) return ErrNoCoordinate.
+func JarCoordinate(file string) (model.GAV, error) {
+	reader, err := zip.OpenReader(file)
+	if err != nil {
+		return model.GAV{}, err
+	}
+	defer reader.Close()
+
+	var found []model.GAV
+	for _, f := range reader.File {
+		if !strings.HasPrefix(f.Name, "META-INF/maven/") || path.Base(f.Name) != "pom.properties" {
+			continue
+		}
+
+		content, err := readZipFile(f)
+		if err != nil {
+			return model.GAV{}, err
+		}
+		properties := util.ParseProperties(string(content))
+		gav := model.GAV{GroupId: properties["groupId"], ArtifactId: properties["artifactId"], Version: properties["version"]}
+		if gav.GroupId != "" && gav.ArtifactId != "" && gav.Version != "" {
+			found = append(found, gav)
+		}
+	}
+	if len(found) != 1 {
+		return model.GAV{}, ErrNoCoordinate
+	}
+	return found[0], nil
+}
+
+// layoutCoordinate returns the coordinate of a file in a maven repository layout, e.g. org/slf4j/slf4j-api/2.0.9/slf4j-api-2.0.9.jar
+func layoutCoordinate(rel string) (model.GAV, bool) {
+	parts := strings.Split(rel, "/")
+	if len(parts) < 4 {
+		return model.GAV{}, false
+	}
+
+	n := len(parts)
+	gav := model.GAV{
+		GroupId:    strings.Join(parts[:n-3], "."),
+		ArtifactId: parts[n-3],
+		Version:    parts[n-2],
+	}
+	if !strings.HasPrefix(parts[n-1], gav.ArtifactId+"-"+gav.Version) {
+		return model.GAV{}, false
+	}
+	return gav, true
+}
+
+func isIgnored(name string) bool {
+	if strings.HasPrefix(name, "maven-metadata") {
+		return true
+	}
+	for _, suffix := range ignoredSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
@@ -0,0 +1,141 @@
+package verify
+
+import (
+	"archive/zip"
+	"crypto/sha512"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/model"
+	"github.com/philippheuer/jvm-repo-rebuild-index/pkg/service"
+)
+
+func writeFile(t *testing.T, file string, content []byte) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(file, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writeJar(t *testing.T, file string, pomProperties string) {
+	t.Helper()
+	f, err := os.Create(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	w := zip.NewWriter(f)
+	entry, err := w.Create("META-INF/maven/com.example/shaded/pom.properties")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = entry.Write([]byte(pomProperties)); err != nil {
+		t.Fatal(err)
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func checksum(content []byte) string {
+	sum := sha512.Sum512(content)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestScan(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "repository")
+	writeFile(t, filepath.Join(dir, "com/example/lib/1.0/lib-1.0.jar"), []byte("jar"))
+	writeFile(t, filepath.Join(dir, "com/example/lib/1.0/lib-1.0.jar.sha1"), []byte("sha1"))
+	writeFile(t, filepath.Join(dir, "com/example/lib/1.0/_remote.repositories"), []byte(""))
+	writeFile(t, filepath.Join(dir, "com/example/lib/maven-metadata-central.xml"), []byte(""))
+	writeJar(t, filepath.Join(dir, "libs.jar"), "#Generated by Maven\ngroupId=com.example\nartifactId=shaded\nversion=2.0\n")
+
+	artifacts, err := Scan(dir)
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+
+	var coordinates []string
+	for _, artifact := range artifacts {
+		coordinates = append(coordinates, artifact.GAV.Coordinate()+" "+artifact.Name())
+	}
+	want := []string{"com.example:lib:1.0 lib-1.0.jar", "com.example:shaded:2.0 libs.jar"}
+	if diff := cmp.Diff(want, coordinates); diff != "" {
+		t.Errorf("Scan() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestScanUnreadableDirectory(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("directory permissions are not enforced for root")
+	}
+
+	dir := filepath.Join(t.TempDir(), "repository")
+	writeFile(t, filepath.Join(dir, "com/example/lib/1.0/lib-1.0.jar"), []byte("jar"))
+	writeFile(t, filepath.Join(dir, "com/example/private/1.0/private-1.0.jar"), []byte("jar"))
+	private := filepath.Join(dir, "com/example/private")
+	if err := os.Chmod(private, 0); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chmod(private, 0755) })
+
+	artifacts, err := Scan(dir)
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if len(artifacts) != 1 || artifacts[0].Name() != "lib-1.0.jar" {
+		t.Errorf("expected only lib-1.0.jar, got %+v", artifacts)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string][]byte{
+		"lib-1.0.jar":         []byte("jar"),
+		"lib-1.0-sources.jar": []byte("tampered"),
+		"lib-1.0-javadoc.jar": []byte("javadoc"),
+	}
+	var artifacts []Artifact
+	for _, name := range []string{"lib-1.0.jar", "lib-1.0-sources.jar", "lib-1.0-javadoc.jar"} {
+		file := filepath.Join(dir, name)
+		writeFile(t, file, files[name])
+		artifacts = append(artifacts, Artifact{Path: file, GAV: model.GAV{GroupId: "com.example", ArtifactId: "lib", Version: "1.0"}})
+	}
+	artifacts = append(artifacts, Artifact{Path: filepath.Join(dir, "other-1.0.jar"), GAV: model.GAV{GroupId: "com.example", ArtifactId: "other", Version: "1.0"}})
+
+	statuses := map[string]service.VersionStatus{
+		"com.example:lib:1.0": {
+			Result: model.LookupResult{Coordinate: "com.example:lib:1.0", Status: model.StatusReproducible},
+			Details: &model.VersionDetails{GroupID: "com.example", ArtifactID: "lib", ResolvedVersion: "1.0", Version: &model.Version{
+				Files: map[string]model.File{
+					"lib-1.0.jar":         {Checksum: checksum([]byte("jar")), Reproducible: true},
+					"lib-1.0-sources.jar": {Checksum: checksum([]byte("sources")), Reproducible: true},
+				},
+			}},
+		},
+		"com.example:other:1.0": {Result: model.LookupResult{Coordinate: "com.example:other:1.0", Status: model.StatusUnknown}},
+	}
+
+	report := Compare(artifacts, statuses)
+
+	var verdicts []Verdict
+	for _, result := range report.Results {
+		verdicts = append(verdicts, result.Verdict)
+	}
+	if diff := cmp.Diff([]Verdict{VerdictMatch, VerdictMismatch, VerdictUnknown, VerdictUnknown}, verdicts); diff != "" {
+		t.Errorf("Compare() mismatch (-want +got):\n%s", diff)
+	}
+	if report.Matches != 1 || report.Mismatches != 1 || report.Unknown != 2 {
+		t.Errorf("unexpected report counts %+v", report)
+	}
+	if report.Results[1].Checksum != checksum([]byte("tampered")) {
+		t.Errorf("expected checksum of the local file, got %s", report.Results[1].Checksum)
+	}
+}